@@ -0,0 +1,102 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package main
+
+import (
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/88250/gulu"
+	"github.com/panjf2000/ants/v2"
+	"github.com/siyuan-note/bazaar/actions/model"
+	"github.com/siyuan-note/bazaar/actions/util"
+)
+
+var logger = gulu.Log.NewLogger(os.Stdout)
+
+// main 将 *.txt 包列表中改名或转移的仓库替换为 GitHub 上的规范名称 owner/repo
+func main() {
+	logger.Infof("bazaar is canonicalizing...")
+
+	for _, typ := range model.Types {
+		canonicalize(typ + ".txt")
+	}
+
+	logger.Infof("canonicalized bazaar")
+}
+
+// canonicalize 查询列表中每个仓库的规范名称并改写列表文件：改名的仓库替换为新名称，
+// 新名称已在列表中时删除旧名称所在行，查询失败或仓库不存在的行保持不变
+func canonicalize(listPath string) {
+	repos, err := util.ParseReposFromTxt(listPath)
+	if nil != err {
+		logger.Fatalf("parse [%s] failed: %s", listPath, err)
+	}
+
+	renames := map[string]string{}
+	lock := sync.Mutex{}
+	waitGroup := &sync.WaitGroup{}
+	p, _ := ants.NewPoolWithFunc(8, func(arg interface{}) {
+		defer waitGroup.Done()
+		repo := arg.(string)
+		fullName, notFound, getErr := util.GetCanonicalRepo(repo)
+		if nil != getErr {
+			logger.Warnf("get canonical name of [%s] failed: %s", repo, getErr)
+			return
+		}
+		if notFound {
+			logger.Warnf("repo [%s] not found", repo)
+			return
+		}
+		// 仅大小写不同时与 stage、check 一致，不视为改名
+		if !strings.EqualFold(fullName, repo) {
+			lock.Lock()
+			renames[repo] = fullName
+			lock.Unlock()
+		}
+	})
+	defer p.Release()
+	for _, entry := range repos {
+		waitGroup.Add(1)
+		p.Invoke(entry.Repo)
+	}
+	waitGroup.Wait()
+
+	if 0 == len(renames) {
+		logger.Infof("[%s] is up to date", listPath)
+		return
+	}
+
+	existing := map[string]bool{}
+	for _, entry := range repos {
+		if _, renamed := renames[entry.Repo]; !renamed {
+			existing[strings.ToLower(entry.Repo)] = true
+		}
+	}
+
+	err = util.RewriteReposTxt(listPath, func(entry *util.RepoEntry) string {
+		fullName, renamed := renames[entry.Repo]
+		if !renamed {
+			return entry.Repo
+		}
+		if existing[strings.ToLower(fullName)] {
+			logger.Infof("[%s] line %d: remove [%s], its canonical name [%s] is already listed", listPath, entry.Line, entry.Repo, fullName)
+			return ""
+		}
+		logger.Infof("[%s] line %d: rename [%s] to [%s]", listPath, entry.Line, entry.Repo, fullName)
+		existing[strings.ToLower(fullName)] = true
+		return fullName
+	})
+	if nil != err {
+		logger.Fatalf("rewrite [%s] failed: %s", listPath, err)
+	}
+}
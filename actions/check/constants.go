@@ -33,6 +33,9 @@ const (
 	FILE_PATH_TEMPLATE_JSON = "template.json"
 	FILE_PATH_THEME_JSON    = "theme.json"
 	FILE_PATH_WIDGET_JSON   = "widget.json"
+
+	SECRET_SCAN_REPO_FILE_LIMIT   = 200 // 凭据扫描时下载内容的仓库文件数上限，其余文件只按文件名判断
+	SECRET_SCAN_FETCH_CONCURRENCY = 4   // 凭据扫描时并发下载仓库文件的数量
 )
 
 var (
@@ -40,4 +43,12 @@ var (
 	BuiltinThemeNames = []string{"daylight", "midnight"}
 	// 内置图标
 	BuiltinIconNames = []string{"ant", "material"}
+
+	// 凭据扫描时不下载内容的二进制文件扩展名
+	SECRET_SCAN_SKIPPED_EXTS = StringSet{
+		".png": nil, ".jpg": nil, ".jpeg": nil, ".gif": nil, ".webp": nil, ".ico": nil, ".bmp": nil,
+		".woff": nil, ".woff2": nil, ".ttf": nil, ".otf": nil, ".eot": nil,
+		".zip": nil, ".gz": nil, ".7z": nil, ".rar": nil,
+		".mp3": nil, ".mp4": nil, ".webm": nil, ".wav": nil, ".pdf": nil, ".wasm": nil,
+	}
 )
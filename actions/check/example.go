@@ -10,12 +10,23 @@
 
 package main
 
+import "github.com/siyuan-note/bazaar/actions/model"
+
 var CheckResultTestExample = CheckResult{
 	Icons: []Icon{
 		{
 			RepoInfo: RepoInfo{
 				Path: "siyuan-note/icon-sample",
 				Home: "https://github.com/siyuan-note/icon-sample",
+				Health: Health{
+					Pass: true,
+					Items: []*HealthItem{
+						{Name: "Public", Pass: true},
+						{Name: "Not archived", Pass: true},
+						{Name: "Not disabled", Pass: true},
+						{Name: "Not a fork without own commits", Pass: true},
+					},
+				},
 			},
 			Release: Release{
 				Pass: true,
@@ -47,6 +58,20 @@ var CheckResultTestExample = CheckResult{
 					URL:  "https://github.com/siyuan-note/icon-sample/blob/95e07499bd1e0880155134628aacc4d07da419aa/README.md",
 				},
 			},
+			Content: Content{
+				Pass: true,
+				Rules: []*model.ContentRule{
+					{Name: "icon.js", Pass: true, Files: []string{"icon.js"}},
+				},
+			},
+			Secrets: Secrets{Pass: true},
+			License: License{
+				Pass: true,
+				ID:   "MIT",
+				Files: []*LicenseFile{
+					{Source: "repository", File: "LICENSE", URL: "https://github.com/siyuan-note/icon-sample/blob/95e07499bd1e0880155134628aacc4d07da419aa/LICENSE", ID: "MIT"},
+				},
+			},
 			Attrs: Attrs{
 				Pass: true,
 				Name: Name{
@@ -124,6 +149,15 @@ var CheckResultTestExample = CheckResult{
 			RepoInfo: RepoInfo{
 				Path: "siyuan-note/plugin-sample",
 				Home: "https://github.com/siyuan-note/plugin-sample",
+				Health: Health{
+					Pass: true,
+					Items: []*HealthItem{
+						{Name: "Public", Pass: true},
+						{Name: "Not archived", Pass: true},
+						{Name: "Not disabled", Pass: true},
+						{Name: "Not a fork without own commits", Pass: true},
+					},
+				},
 			},
 			Release: Release{
 				Pass: true,
@@ -155,6 +189,23 @@ var CheckResultTestExample = CheckResult{
 					URL:  "https://github.com/siyuan-note/plugin-sample/blob/979f77bbeec0bc9d123305a7e18d1936ae67b009/README.md",
 				},
 			},
+			Content: Content{
+				Pass: true,
+				Rules: []*model.ContentRule{
+					{Name: "index.js", Pass: true, Files: []string{"index.js"}},
+				},
+				Security: []*model.SecurityFinding{
+					{Rule: "fs", Severity: model.SecurityMedium, File: "index.js", Line: 1, Count: 2, Message: "requires fs and can access files outside the workspace", Evidence: `require("fs")`},
+				},
+			},
+			Secrets: Secrets{Pass: true},
+			License: License{
+				Pass: true,
+				ID:   "MIT",
+				Files: []*LicenseFile{
+					{Source: "repository", File: "LICENSE", URL: "https://github.com/siyuan-note/plugin-sample/blob/979f77bbeec0bc9d123305a7e18d1936ae67b009/LICENSE", ID: "MIT"},
+				},
+			},
 			Attrs: Attrs{
 				Pass: true,
 				Name: Name{
@@ -176,12 +227,40 @@ var CheckResultTestExample = CheckResult{
 					Pass:  true,
 					Value: "https://github.com/siyuan-note/plugin-sample",
 				},
+				Readme: Readme{
+					Pass: true,
+					Files: []*ReadmeFile{
+						{
+							File: File{
+								Pass: true,
+								URL:  "https://github.com/siyuan-note/plugin-sample/blob/979f77bbeec0bc9d123305a7e18d1936ae67b009/README_zh_CN.md",
+							},
+							Locale: "zh_CN",
+							Path:   "README_zh_CN.md",
+						},
+					},
+				},
+				Keywords: Keywords{
+					Normalized: []*model.KeywordChange{
+						{From: "Sample ", To: "sample"},
+						{From: "插件", To: "plugin"},
+					},
+					Unknown: []string{"sample"},
+				},
 			},
 		},
 		{
 			RepoInfo: RepoInfo{
 				Path: "siyuan-note/plugin-sample",
 				Home: "https://github.com/siyuan-note/plugin-sample",
+				Health: Health{
+					Items: []*HealthItem{
+						{Name: "Public", Pass: true},
+						{Name: "Not archived", Pass: false, Detail: "archived repositories are read-only and can not receive fixes"},
+						{Name: "Not disabled", Pass: true},
+						{Name: "Not a fork without own commits", Pass: true},
+					},
+				},
 			},
 			Release: Release{
 				Pass: false,
@@ -207,6 +286,12 @@ var CheckResultTestExample = CheckResult{
 					Pass: false,
 				},
 			},
+			Secrets: Secrets{
+				Findings: []*model.SecretFinding{
+					{Source: model.SecretSourceRepository, File: ".env", Kind: "forbidden file: .env file"},
+					{Source: model.SecretSourcePackageZip, File: "index.js", Line: 12, Kind: "GitHub token", Redacted: "ghp_****************"},
+				},
+			},
 			Attrs: Attrs{
 				Pass: false,
 				Name: Name{
@@ -231,6 +316,15 @@ var CheckResultTestExample = CheckResult{
 			RepoInfo: RepoInfo{
 				Path: "siyuan-note/template-sample",
 				Home: "https://github.com/siyuan-note/template-sample",
+				Health: Health{
+					Pass: true,
+					Items: []*HealthItem{
+						{Name: "Public", Pass: true},
+						{Name: "Not archived", Pass: true},
+						{Name: "Not disabled", Pass: true},
+						{Name: "Not a fork without own commits", Pass: true},
+					},
+				},
 			},
 			Release: Release{
 				Pass: true,
@@ -262,6 +356,20 @@ var CheckResultTestExample = CheckResult{
 					URL:  "https://github.com/siyuan-note/template-sample/blob/280b81c2ca51c2fccb65662a56c02fc2fb050a9d/template.json",
 				},
 			},
+			Content: Content{
+				Pass: true,
+				Rules: []*model.ContentRule{
+					{Name: "*.md", Pass: true, Files: []string{"templates/sample.md"}},
+				},
+			},
+			Secrets: Secrets{Pass: true},
+			License: License{
+				Pass: true,
+				ID:   "MIT",
+				Files: []*LicenseFile{
+					{Source: "repository", File: "LICENSE", URL: "https://github.com/siyuan-note/template-sample/blob/280b81c2ca51c2fccb65662a56c02fc2fb050a9d/LICENSE", ID: "MIT"},
+				},
+			},
 			Attrs: Attrs{
 				Pass: true,
 				Name: Name{
@@ -337,6 +445,15 @@ var CheckResultTestExample = CheckResult{
 			RepoInfo: RepoInfo{
 				Path: "siyuan-note/theme-sample",
 				Home: "https://github.com/siyuan-note/theme-sample",
+				Health: Health{
+					Pass: true,
+					Items: []*HealthItem{
+						{Name: "Public", Pass: true},
+						{Name: "Not archived", Pass: true},
+						{Name: "Not disabled", Pass: true},
+						{Name: "Not a fork without own commits", Pass: true},
+					},
+				},
 			},
 			Release: Release{
 				Pass: true,
@@ -368,6 +485,23 @@ var CheckResultTestExample = CheckResult{
 					URL:  "https://github.com/siyuan-note/theme-sample/blob/14665b04a381b8265ed27e5a4ad0156e7c0c05cc/theme.json",
 				},
 			},
+			Content: Content{
+				Pass: true,
+				Rules: []*model.ContentRule{
+					{Name: "theme.css", Pass: true, Files: []string{"theme.css"}},
+				},
+				CSS: []*model.CSSIssue{
+					{File: "theme.css", Line: 1, Column: 1, Severity: model.SeverityWarning, Message: "@import of remote URL https://fonts.googleapis.com/css2?family=Inter is loaded at runtime and bypasses review"},
+				},
+			},
+			Secrets: Secrets{Pass: true},
+			License: License{
+				Pass: true,
+				ID:   "MIT",
+				Files: []*LicenseFile{
+					{Source: "repository", File: "LICENSE", URL: "https://github.com/siyuan-note/theme-sample/blob/14665b04a381b8265ed27e5a4ad0156e7c0c05cc/LICENSE", ID: "MIT"},
+				},
+			},
 			Attrs: Attrs{
 				Pass: true,
 				Name: Name{
@@ -445,6 +579,15 @@ var CheckResultTestExample = CheckResult{
 			RepoInfo: RepoInfo{
 				Path: "siyuan-note/widget-sample",
 				Home: "https://github.com/siyuan-note/widget-sample",
+				Health: Health{
+					Pass: true,
+					Items: []*HealthItem{
+						{Name: "Public", Pass: true},
+						{Name: "Not archived", Pass: true},
+						{Name: "Not disabled", Pass: true},
+						{Name: "Not a fork without own commits", Pass: true},
+					},
+				},
 			},
 			Release: Release{
 				Pass: true,
@@ -476,6 +619,20 @@ var CheckResultTestExample = CheckResult{
 					URL:  "https://github.com/siyuan-note/widget-sample/blob/272314c056116dc32afbe61c85d541a509157948/widget.json",
 				},
 			},
+			Content: Content{
+				Pass: true,
+				Rules: []*model.ContentRule{
+					{Name: "index.html", Pass: true, Files: []string{"index.html"}},
+				},
+			},
+			Secrets: Secrets{Pass: true},
+			License: License{
+				Pass: true,
+				ID:   "MIT",
+				Files: []*LicenseFile{
+					{Source: "repository", File: "LICENSE", URL: "https://github.com/siyuan-note/widget-sample/blob/272314c056116dc32afbe61c85d541a509157948/LICENSE", ID: "MIT"},
+				},
+			},
 			Attrs: Attrs{
 				Pass: true,
 				Name: Name{
@@ -17,8 +17,11 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"text/template"
 	"time"
 
@@ -26,6 +29,7 @@ import (
 	"github.com/google/go-github/v52/github"
 	"github.com/panjf2000/ants/v2"
 	"github.com/parnurzeal/gorequest"
+	"github.com/siyuan-note/bazaar/actions/model"
 	"github.com/siyuan-note/bazaar/actions/util"
 )
 
@@ -63,6 +67,11 @@ var (
 	REQUEST_RETRY_COUNT    = 3                // 请求重试次数
 	REQUEST_RETRY_DURATION = 10 * time.Second // 请求重试间隔时间
 
+	SECRET_SCAN_TIMEOUT      = 2 * time.Minute  // 凭据扫描下载仓库文件的总时间上限
+	SECRET_SCAN_FILE_TIMEOUT = 10 * time.Second // 凭据扫描下载单个仓库文件的超时时间
+
+	keywordTaxonomy = model.Taxonomy{} // 关键词分类表，取自 bazaar head
+
 	logger        = gulu.Log.NewLogger(os.Stdout)
 	githubContext = context.Background()
 	githubClient  = github.NewTokenClient(githubContext, GITHUB_TOKEN)
@@ -107,6 +116,14 @@ func main() {
 		panic(err)
 	}
 
+	// 加载关键词分类表，用于提示被规范化及未知的关键词
+	keywordsPath := filepath.Join(BAZAAR_HEAD_PATH, model.KeywordsFileName)
+	if taxonomy, err := model.LoadTaxonomy(keywordsPath); err != nil {
+		logger.Warnf("load keyword taxonomy [%s] failed: %s", keywordsPath, err)
+	} else {
+		keywordTaxonomy = taxonomy
+	}
+
 	var parseErrorMu sync.Mutex
 	wg := &sync.WaitGroup{}
 	wg.Add(5)
@@ -134,17 +151,10 @@ func parseReposFromRootTxt(filePath string) (paths []string, pathSet StringSet,
 	paths = make([]string, 0, len(repos))
 	pathSet = make(StringSet, len(repos))
 	nameToOwner = make(map[string]string, len(repos))
-	for _, s := range repos {
-		parts := strings.Split(s, "/")
-		if len(parts) != 2 {
-			err = fmt.Errorf("invalid repo path: %s", s)
-			return
-		}
-		owner := parts[0]
-		name := parts[1]
-		paths = append(paths, s)
-		pathSet[s] = nil
-		nameToOwner[name] = owner
+	for _, entry := range repos {
+		paths = append(paths, entry.Repo)
+		pathSet[entry.Repo] = nil
+		nameToOwner[entry.Name] = entry.Owner
 	}
 	return
 }
@@ -152,17 +162,16 @@ func parseReposFromRootTxt(filePath string) (paths []string, pathSet StringSet,
 // loadAllTypesNameSet 加载所有类型的 stage nameSet（使用 package.name），用于跨类型 name 唯一性检查
 func loadAllTypesNameSet() (StringSet, error) {
 	allTypesNameSet := make(StringSet)
-	jsonFiles := []string{"icons.json", "plugins.json", "templates.json", "themes.json", "widgets.json"}
 
-	for _, jsonFile := range jsonFiles {
+	for _, typ := range model.Types {
 		// 从 stage 文件夹读取 JSON 文件
-		filePath := filepath.Join(BAZAAR_HEAD_PATH, "stage", jsonFile)
-		nameSet, err := parseNamesFromStageJSON(filePath)
+		filePath := filepath.Join(BAZAAR_HEAD_PATH, "stage", typ+".json")
+		stageFile, err := model.LoadStageFile(filePath, typ)
 		if err != nil {
 			return nil, fmt.Errorf("load stage repos [%s] failed: %s", filePath, err)
 		}
 		// 合并所有类型的 nameSet
-		for name := range nameSet {
+		for _, name := range stageFile.Names() {
 			allTypesNameSet[name] = nil
 		}
 	}
@@ -170,48 +179,6 @@ func loadAllTypesNameSet() (StringSet, error) {
 	return allTypesNameSet, nil
 }
 
-// parseNamesFromStageJSON 从 stage JSON 文件中解析出所有 package.name
-func parseNamesFromStageJSON(filePath string) (StringSet, error) {
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		return nil, err
-	}
-
-	var m map[string]interface{}
-	if err = gulu.JSON.UnmarshalJSON(data, &m); err != nil {
-		return nil, err
-	}
-
-	repos, _ := m["repos"].([]interface{})
-	if repos == nil {
-		return make(StringSet), nil
-	}
-
-	nameSet := make(StringSet)
-	for _, r := range repos {
-		repoMap, ok := r.(map[string]interface{})
-		if !ok {
-			continue
-		}
-
-		// 获取 package 对象
-		pkg, ok := repoMap["package"].(map[string]interface{})
-		if !ok {
-			continue
-		}
-
-		// 获取 package.name
-		name, ok := pkg["name"].(string)
-		if !ok || name == "" {
-			continue
-		}
-
-		nameSet[name] = nil
-	}
-
-	return nameSet, nil
-}
-
 // checkRepos 检查集市资源仓库列表
 func checkRepos(
 	resourceType ResourceType,
@@ -317,6 +284,30 @@ func checkRepos(
 		}
 	}
 
+	// 仓库改名或转移后，PR 用新名称替换旧名称：旧名称的规范名称与新增的仓库相同，同样视为更换维护者
+	for _, path := range basePaths {
+		if isKeyInSet(path, headSet) {
+			continue // 本 PR 未删除
+		}
+		parts := strings.Split(path, "/")
+		// REF https://pkg.go.dev/github.com/google/go-github/v52/github#RepositoriesService.Get
+		githubRepo, _, err := githubClient.Repositories.Get(githubContext, parts[0], parts[1])
+		if err != nil {
+			logger.Warnf("get repo [%s] failed: %s", path, err)
+			continue
+		}
+		fullName := githubRepo.GetFullName()
+		if strings.EqualFold(fullName, path) {
+			continue
+		}
+		for _, newPath := range newRepos {
+			if strings.EqualFold(newPath, fullName) && !slices.Contains(maintainerChanged, newPath) {
+				logger.Infof("repo [%s] was renamed or transferred to [%s]", path, newPath)
+				maintainerChanged = append(maintainerChanged, newPath)
+			}
+		}
+	}
+
 	// 新增与更换维护者合并为待检查列表，统一做 release/文件/属性/name 唯一性检查（更换维护者按新集市包处理）
 	maintainerChangedSet := make(StringSet, len(maintainerChanged))
 	for _, path := range maintainerChanged {
@@ -405,6 +396,7 @@ func checkRepo(
 		Path:  repoPath,
 		Home:  buildRepoHomeURL(repoOwner, repoName),
 	}
+	repoInfo.Health = *checkRepoHealth(repoOwner, repoName)
 	releaseCheckResult := checkRepoLatestRelease(repoOwner, repoName)
 
 	if releaseCheckResult.LatestRelease.Hash != "" {
@@ -433,10 +425,20 @@ func checkRepo(
 			manifestFilePath,
 		) // 清单文件下载地址
 
-		if attrsCheckResult, err = checkManifestAttrs(manifestFileUrl); err != nil {
+		var manifest model.Manifest
+		if attrsCheckResult, manifest, err = checkManifestAttrs(manifestFileUrl, resourceTypeName(resourceType)); err != nil {
 			logger.Warnf("check repo [%s] manifest file [%s] failed: %s", repoPath, manifestFileUrl, err)
 			attrsCheckResult = &Attrs{} // 避免后续访问 nil 导致模板渲染失败
 		}
+		if manifest != nil {
+			// 检查清单中声明的多语言 README 文件是否存在
+			attrsCheckResult.Readme = *checkReadmeFiles(
+				repoOwner,
+				repoName,
+				releaseCheckResult.LatestRelease.Hash,
+				manifest.Base(),
+			)
+		}
 		if attrsCheckResult != nil {
 			// 有效性检查
 			attrsCheckResult.Name.Valid = isValidName(attrsCheckResult.Name.Value)
@@ -487,7 +489,9 @@ func checkRepo(
 		attrsCheckResult.Pass = attrsCheckResult.Name.Pass &&
 			attrsCheckResult.Version.Pass &&
 			attrsCheckResult.Author.Pass &&
-			attrsCheckResult.URL.Pass
+			attrsCheckResult.URL.Pass &&
+			attrsCheckResult.Readme.Pass &&
+			attrsCheckResult.Enums.Pass
 
 		// 检查文件
 		var filesCheckResult interface{} // 文件检查结果
@@ -655,6 +659,33 @@ func checkRepo(
 			return
 		}
 
+		// 检查 package.zip 中按类型必须包含的内容
+		contentCheckResult := &Content{Error: "package.zip not found"}
+		var packageZip *model.PackageZip
+		if releaseCheckResult.LatestRelease.PackageZip.Pass {
+			contentCheckResult, packageZip = checkPackageContent(
+				releaseCheckResult.LatestRelease.PackageZip.URL,
+				resourceTypeName(resourceType),
+				manifest,
+			)
+		}
+
+		// 扫描 package.zip 及发行版提交时的仓库文件中泄露的凭据
+		secretsCheckResult := checkSecrets(
+			repoOwner,
+			repoName,
+			releaseCheckResult.LatestRelease.Hash,
+			packageZip,
+		)
+
+		// 识别仓库及 package.zip 中的许可证
+		licenseCheckResult := checkLicense(
+			repoOwner,
+			repoName,
+			releaseCheckResult.LatestRelease.Hash,
+			packageZip,
+		)
+
 		// 返回检查结果
 		switch resourceType {
 		case icons:
@@ -662,6 +693,9 @@ func checkRepo(
 				RepoInfo: *repoInfo,
 				Release:  *releaseCheckResult,
 				Files:    *filesCheckResult.(*IconFiles),
+				Content:  *contentCheckResult,
+				Secrets:  *secretsCheckResult,
+				License:  *licenseCheckResult,
 				Attrs:    *attrsCheckResult,
 			}
 		case plugins:
@@ -669,6 +703,9 @@ func checkRepo(
 				RepoInfo: *repoInfo,
 				Release:  *releaseCheckResult,
 				Files:    *filesCheckResult.(*PluginFiles),
+				Content:  *contentCheckResult,
+				Secrets:  *secretsCheckResult,
+				License:  *licenseCheckResult,
 				Attrs:    *attrsCheckResult,
 			}
 		case templates:
@@ -676,6 +713,9 @@ func checkRepo(
 				RepoInfo: *repoInfo,
 				Release:  *releaseCheckResult,
 				Files:    *filesCheckResult.(*TemplateFiles),
+				Content:  *contentCheckResult,
+				Secrets:  *secretsCheckResult,
+				License:  *licenseCheckResult,
 				Attrs:    *attrsCheckResult,
 			}
 		case themes:
@@ -683,6 +723,9 @@ func checkRepo(
 				RepoInfo: *repoInfo,
 				Release:  *releaseCheckResult,
 				Files:    *filesCheckResult.(*ThemeFiles),
+				Content:  *contentCheckResult,
+				Secrets:  *secretsCheckResult,
+				License:  *licenseCheckResult,
 				Attrs:    *attrsCheckResult,
 			}
 		case widgets:
@@ -690,6 +733,9 @@ func checkRepo(
 				RepoInfo: *repoInfo,
 				Release:  *releaseCheckResult,
 				Files:    *filesCheckResult.(*WidgetFiles),
+				Content:  *contentCheckResult,
+				Secrets:  *secretsCheckResult,
+				License:  *licenseCheckResult,
 				Attrs:    *attrsCheckResult,
 			}
 		}
@@ -738,6 +784,73 @@ func checkRepo(
 	logger.Infof("finish repo check [%s]", repoPath)
 }
 
+// checkRepoHealth 检查仓库状态：公开、未归档、未被禁用，且不是没有自己提交的派生仓库
+func checkRepoHealth(
+	repoOwner string,
+	repoName string,
+) (healthCheckResult *Health) {
+	healthCheckResult = &Health{}
+
+	// REF https://pkg.go.dev/github.com/google/go-github/v52/github#RepositoriesService.Get
+	githubRepo, _, err := githubClient.Repositories.Get(githubContext, repoOwner, repoName)
+	if nil != err {
+		logger.Warnf("get repo [%s/%s] failed: %s", repoOwner, repoName, err)
+		healthCheckResult.Error = "get repository information failed"
+		return
+	}
+
+	health := &model.RepoHealth{
+		Archived:   githubRepo.GetArchived(),
+		Disabled:   githubRepo.GetDisabled(),
+		Private:    githubRepo.GetPrivate(),
+		Fork:       githubRepo.GetFork(),
+		Visibility: githubRepo.GetVisibility(),
+	}
+	public := &HealthItem{Name: "Public", Pass: health.Public()}
+	if !public.Pass {
+		public.Detail = health.ExcludeReason()
+	}
+	archived := &HealthItem{Name: "Not archived", Pass: !health.Archived}
+	if !archived.Pass {
+		archived.Detail = "archived repositories are read-only and can not receive fixes"
+	}
+	disabled := &HealthItem{Name: "Not disabled", Pass: !health.Disabled}
+	if !disabled.Pass {
+		disabled.Detail = "repository is disabled by GitHub"
+	}
+	fork := &HealthItem{Name: "Not a fork without own commits", Pass: true}
+	if health.Fork {
+		fork.Pass, fork.Detail = checkForkAhead(githubRepo)
+	}
+	healthCheckResult.Items = []*HealthItem{public, archived, disabled, fork}
+
+	healthCheckResult.Pass = true
+	for _, item := range healthCheckResult.Items {
+		healthCheckResult.Pass = healthCheckResult.Pass && item.Pass
+	}
+	return
+}
+
+// checkForkAhead 判断派生仓库的默认分支是否有上游仓库之外的提交，没有时视为未修改的派生仓库
+func checkForkAhead(githubRepo *github.Repository) (pass bool, detail string) {
+	parent := githubRepo.GetParent()
+	if parent == nil {
+		return true, ""
+	}
+
+	// REF https://pkg.go.dev/github.com/google/go-github/v52/github#RepositoriesService.CompareCommits
+	head := githubRepo.GetOwner().GetLogin() + ":" + githubRepo.GetDefaultBranch()
+	comparison, _, err := githubClient.Repositories.CompareCommits(githubContext, parent.GetOwner().GetLogin(), parent.GetName(), parent.GetDefaultBranch(), head, nil)
+	if nil != err {
+		logger.Warnf("compare fork [%s] with parent [%s] failed: %s", githubRepo.GetFullName(), parent.GetFullName(), err)
+		return true, ""
+	}
+	if comparison.GetAheadBy() == 0 {
+		return false, fmt.Sprintf("fork of [%s](%s) has no commits of its own", parent.GetFullName(), parent.GetHTMLURL())
+	}
+	return true, ""
+}
+
 // checkRepoLatestRelease 检查最新发行信息
 func checkRepoLatestRelease(
 	repoOwner string,
@@ -878,8 +991,257 @@ func checkFileExist(
 	return
 }
 
-// checkManifestAttrs 检查清单属性
-func checkManifestAttrs(fileURL string) (attrsCheckResult *Attrs, err error) {
+// checkReadmeFiles 检查清单 readme 字段中各 locale 声明的 README 文件是否存在，未声明时视为通过
+func checkReadmeFiles(
+	repoOwner string,
+	repoName string,
+	hash string,
+	pkg *model.Package,
+) (readmeCheckResult *Readme) {
+	readmeCheckResult = &Readme{Pass: true}
+
+	locales := make([]string, 0, len(pkg.Readme))
+	for locale, readmePath := range pkg.Readme {
+		if strings.TrimSpace(readmePath) != "" {
+			locales = append(locales, locale)
+		}
+	}
+	sort.Strings(locales)
+
+	for _, locale := range locales {
+		readmeFile := &ReadmeFile{Locale: locale, Path: pkg.Readme[locale]}
+		if normalized, ok := model.NormalizeReadmePath(pkg.Readme[locale]); ok {
+			readmeFile.Path = normalized
+			fileCheckResult, err := checkFileExist(
+				repoOwner,
+				repoName,
+				hash,
+				normalized,
+			)
+			if err != nil {
+				logger.Warn(err.Error())
+			}
+			readmeFile.File = *fileCheckResult
+		} else {
+			logger.Warnf("repo [%s/%s] readme path [%s: %s] is invalid", repoOwner, repoName, locale, pkg.Readme[locale])
+		}
+
+		readmeCheckResult.Pass = readmeCheckResult.Pass && readmeFile.Pass
+		readmeCheckResult.Files = append(readmeCheckResult.Files, readmeFile)
+	}
+	return
+}
+
+// checkPackageContent 下载 package.zip 并按类型检查其中必须包含的内容，typ 为 model.TypePlugins 等包类型，
+// manifest 为解析后的清单，主题据其 modes 检查样式，解析失败时为 nil
+func checkPackageContent(packageZipURL string, typ string, manifest model.Manifest) (contentCheckResult *Content, packageZip *model.PackageZip) {
+	contentCheckResult = &Content{}
+	response, data, errs := gorequest.
+		New().
+		Get(packageZipURL).
+		Set("User-Agent", util.UserAgent).
+		Retry(REQUEST_RETRY_COUNT, REQUEST_RETRY_DURATION).
+		Timeout(REQUEST_TIMEOUT).
+		EndBytes()
+	if nil != errs {
+		logger.Warnf("HTTP GET request [%s] failed: %s", packageZipURL, errs)
+		contentCheckResult.Error = "download package.zip failed"
+		return
+	}
+	if response.StatusCode != http.StatusOK {
+		logger.Warnf("HTTP GET request [%s] failed: %s", packageZipURL, response.Status)
+		contentCheckResult.Error = "download package.zip failed: " + response.Status
+		return
+	}
+
+	packageZip, err := model.OpenPackageZip(data)
+	if err != nil {
+		packageZip = nil
+		logger.Warnf("open package.zip [%s] failed: %s", packageZipURL, err)
+		contentCheckResult.Error = "package.zip is not a valid zip file"
+		return
+	}
+
+	contentCheckResult.Rules = packageZip.CheckRequiredContent(typ)
+	contentCheckResult.Syntax = packageZip.CheckJSSyntax(typ)
+	if contentCheckResult.Syntax != nil {
+		logger.Warnf("package.zip [%s] file [%s] has syntax error: %s", packageZipURL, contentCheckResult.Syntax.File, contentCheckResult.Syntax.Message)
+	}
+	contentCheckResult.Pass = contentCheckResult.Syntax == nil
+	contentCheckResult.Security = packageZip.ScanJSSecurity(typ)
+	if typ == model.TypeThemes {
+		var modes []string
+		if theme, ok := manifest.(*model.ThemePackage); ok {
+			modes = theme.Modes
+		}
+		contentCheckResult.CSS = packageZip.CheckThemeCSS(modes)
+		for _, issue := range contentCheckResult.CSS {
+			if issue.Severity == model.SeverityError {
+				logger.Warnf("package.zip [%s] file [%s] has CSS error: %s", packageZipURL, issue.File, issue.Message)
+				contentCheckResult.Pass = false
+			}
+		}
+	}
+	for _, rule := range contentCheckResult.Rules {
+		contentCheckResult.Pass = contentCheckResult.Pass && rule.Pass
+	}
+	return
+}
+
+// checkSecrets 扫描 package.zip 及发行版提交时仓库中的文件，查找泄露的凭据与不允许发布的凭据文件；
+// packageZip 为 nil 时只扫描仓库，跳过的二进制或过大文件只按文件名判断
+func checkSecrets(
+	repoOwner string,
+	repoName string,
+	hash string,
+	packageZip *model.PackageZip,
+) (secretsCheckResult *Secrets) {
+	secretsCheckResult = &Secrets{}
+	if packageZip != nil {
+		secretsCheckResult.Findings = packageZip.ScanSecrets()
+	}
+
+	// REF https://pkg.go.dev/github.com/google/go-github/v52/github#GitService.GetTree
+	tree, _, err := githubClient.Git.GetTree(githubContext, repoOwner, repoName, hash, true)
+	if nil != err {
+		logger.Warnf("get repo [%s/%s] tree [%s] failed: %s", repoOwner, repoName, hash, err)
+		secretsCheckResult.Error = "list repository files failed"
+		return
+	}
+	if tree.GetTruncated() {
+		logger.Warnf("repo [%s/%s] tree [%s] is truncated", repoOwner, repoName, hash)
+	}
+
+	var candidates []string
+	for _, entry := range tree.Entries {
+		if entry.GetType() != "blob" {
+			continue
+		}
+		filePath := entry.GetPath()
+		if len(candidates) >= SECRET_SCAN_REPO_FILE_LIMIT || !isSecretScanCandidate(filePath, entry.GetSize()) {
+			secretsCheckResult.Findings = append(secretsCheckResult.Findings, model.ScanFileSecrets(model.SecretSourceRepository, filePath, nil)...)
+			continue
+		}
+		candidates = append(candidates, filePath)
+	}
+
+	// 并发下载文件内容，超过总时间上限后剩余文件只按文件名判断
+	contents := make([][]byte, len(candidates))
+	deadline := time.Now().Add(SECRET_SCAN_TIMEOUT)
+	var timedOut atomic.Bool
+	waitGroup := &sync.WaitGroup{}
+	p, _ := ants.NewPoolWithFunc(SECRET_SCAN_FETCH_CONCURRENCY, func(arg interface{}) {
+		defer waitGroup.Done()
+		i := arg.(int)
+		timeout := min(SECRET_SCAN_FILE_TIMEOUT, time.Until(deadline))
+		if timeout <= 0 {
+			timedOut.Store(true)
+			return
+		}
+		contents[i] = getSecretScanFile(buildFileRawURL(repoOwner, repoName, hash, candidates[i]), timeout)
+	})
+	for i := range candidates {
+		waitGroup.Add(1)
+		p.Invoke(i)
+	}
+	waitGroup.Wait()
+	p.Release()
+	if timedOut.Load() {
+		logger.Warnf("repo [%s/%s] secret scan exceeded %s, remaining files are checked by name only", repoOwner, repoName, SECRET_SCAN_TIMEOUT)
+	}
+
+	for i, filePath := range candidates {
+		secretsCheckResult.Findings = append(secretsCheckResult.Findings, model.ScanFileSecrets(model.SecretSourceRepository, filePath, contents[i])...)
+	}
+
+	secretsCheckResult.Pass = len(secretsCheckResult.Findings) == 0
+	return
+}
+
+// getSecretScanFile 下载凭据扫描的仓库文件，不重试，失败时返回 nil
+func getSecretScanFile(fileURL string, timeout time.Duration) []byte {
+	response, data, errs := gorequest.
+		New().
+		Get(fileURL).
+		Set("User-Agent", util.UserAgent).
+		Timeout(timeout).
+		EndBytes()
+	if nil != errs {
+		logger.Warnf("HTTP GET request [%s] failed: %v", fileURL, errs)
+		return nil
+	}
+	if response.StatusCode != http.StatusOK {
+		logger.Warnf("HTTP GET request [%s] failed: %d", fileURL, response.StatusCode)
+		return nil
+	}
+	return data
+}
+
+// checkLicense 识别发行版提交时仓库根目录及 package.zip 根目录下许可证文件的 SPDX 许可证，任一识别成功即通过，优先采用仓库中的许可证
+func checkLicense(
+	repoOwner string,
+	repoName string,
+	hash string,
+	packageZip *model.PackageZip,
+) (licenseCheckResult *License) {
+	licenseCheckResult = &License{}
+
+	// REF https://pkg.go.dev/github.com/google/go-github/v52/github#RepositoriesService.GetContents
+	_, dirContents, _, err := githubClient.Repositories.GetContents(githubContext, repoOwner, repoName, "", &github.RepositoryContentGetOptions{Ref: hash})
+	if nil != err {
+		logger.Warnf("get repo [%s/%s] root contents [%s] failed: %s", repoOwner, repoName, hash, err)
+	}
+	for _, content := range dirContents {
+		filePath := content.GetPath()
+		if content.GetType() != "file" || !model.IsLicenseFile(filePath) {
+			continue
+		}
+
+		licenseFile := &LicenseFile{
+			Source: "repository",
+			File:   filePath,
+			URL:    buildFilePreviewURL(repoOwner, repoName, hash, filePath),
+		}
+		fileURL := buildFileRawURL(repoOwner, repoName, hash, filePath)
+		response, data, errs := gorequest.
+			New().
+			Get(fileURL).
+			Set("User-Agent", util.UserAgent).
+			Retry(REQUEST_RETRY_COUNT, REQUEST_RETRY_DURATION).
+			Timeout(REQUEST_TIMEOUT).
+			EndBytes()
+		if nil != errs {
+			logger.Warnf("HTTP GET request [%s] failed: %v", fileURL, errs)
+		} else if response.StatusCode != http.StatusOK {
+			logger.Warnf("HTTP GET request [%s] failed: %d", fileURL, response.StatusCode)
+		} else {
+			licenseFile.ID = model.DetectLicense(data)
+		}
+		licenseCheckResult.Files = append(licenseCheckResult.Files, licenseFile)
+	}
+
+	if packageZip != nil {
+		if file, id := packageZip.DetectLicense(); file != "" {
+			licenseCheckResult.Files = append(licenseCheckResult.Files, &LicenseFile{
+				Source: "package.zip",
+				File:   file,
+				ID:     id,
+			})
+		}
+	}
+
+	for _, licenseFile := range licenseCheckResult.Files {
+		if licenseFile.ID != "" {
+			licenseCheckResult.ID = licenseFile.ID
+			break
+		}
+	}
+	licenseCheckResult.Pass = licenseCheckResult.ID != ""
+	return
+}
+
+// checkManifestAttrs 检查清单属性，typ 为 model.TypePlugins 等包类型
+func checkManifestAttrs(fileURL string, typ string) (attrsCheckResult *Attrs, manifest model.Manifest, err error) {
 	attrsCheckResult = &Attrs{}
 	response, data, errs := gorequest.
 		New().
@@ -897,36 +1259,37 @@ func checkManifestAttrs(fileURL string) (attrsCheckResult *Attrs, err error) {
 		return
 	}
 
-	// 解析清单
-	manifest := map[string]interface{}{}
-	if err = gulu.JSON.UnmarshalJSON(data, &manifest); nil != err {
+	// 按类型解析清单，字段类型不符时返回错误而不是 panic
+	manifest, err = model.ParseManifest(data, typ)
+	if nil != err {
+		manifest = nil
 		return
 	}
+	pkg := manifest.Base()
 
 	// 检查清单文件
-	if name := manifest["name"]; name != nil {
-		if value := name.(string); value != "" {
-			attrsCheckResult.Name.Value = value
-			attrsCheckResult.Name.Exist = true
-		}
+	if pkg.Name != "" {
+		attrsCheckResult.Name.Value = pkg.Name
+		attrsCheckResult.Name.Exist = true
 	}
-	if version := manifest["version"]; version != nil {
-		if value := version.(string); value != "" {
-			attrsCheckResult.Version.Value = value
-			attrsCheckResult.Version.Pass = true
-		}
+	if pkg.Version != "" {
+		attrsCheckResult.Version.Value = pkg.Version
+		attrsCheckResult.Version.Pass = true
 	}
-	if author := manifest["author"]; author != nil {
-		if value := author.(string); value != "" {
-			attrsCheckResult.Author.Value = value
-			attrsCheckResult.Author.Pass = true
-		}
+	if pkg.Author != "" {
+		attrsCheckResult.Author.Value = pkg.Author
+		attrsCheckResult.Author.Pass = true
 	}
-	if url := manifest["url"]; url != nil {
-		if value := url.(string); value != "" {
-			attrsCheckResult.URL.Value = value
-			attrsCheckResult.URL.Pass = true
-		}
+	if pkg.URL != "" {
+		attrsCheckResult.URL.Value = pkg.URL
+		attrsCheckResult.URL.Pass = true
 	}
+
+	attrsCheckResult.Enums.Invalid = model.ValidateEnums(manifest, false)
+	attrsCheckResult.Enums.Pass = len(attrsCheckResult.Enums.Invalid) == 0
+
+	keywords := keywordTaxonomy.Canonicalize(pkg.Keywords)
+	attrsCheckResult.Keywords.Normalized = keywords.Normalized
+	attrsCheckResult.Keywords.Unknown = keywords.Unknown
 	return
 }
@@ -10,6 +10,8 @@
 
 package main
 
+import "github.com/siyuan-note/bazaar/actions/model"
+
 type ResourceType int                 // 资源类型
 type StringSet map[string]interface{} // 字符串集合
 
@@ -36,6 +38,9 @@ type Icon struct {
 	RepoInfo          RepoInfo  `json:"repo"`               // 仓库
 	Release           Release   `json:"release"`            // 发行版
 	Files             IconFiles `json:"files"`              // 文件
+	Content           Content   `json:"content"`            // package.zip 内容
+	Secrets           Secrets   `json:"secrets"`            // 凭据泄露扫描
+	License           License   `json:"license"`            // 许可证
 	Attrs             Attrs     `json:"attrs"`              // 属性
 	MaintainerChanged bool      `json:"maintainer_changed"` // 更换了维护者
 }
@@ -54,6 +59,9 @@ type Plugin struct {
 	RepoInfo          RepoInfo    `json:"repo"`               // 仓库
 	Release           Release     `json:"release"`            // 发行版
 	Files             PluginFiles `json:"files"`              // 文件
+	Content           Content     `json:"content"`            // package.zip 内容
+	Secrets           Secrets     `json:"secrets"`            // 凭据泄露扫描
+	License           License     `json:"license"`            // 许可证
 	Attrs             Attrs       `json:"attrs"`              // 属性
 	MaintainerChanged bool        `json:"maintainer_changed"` // 更换了维护者
 }
@@ -72,6 +80,9 @@ type Template struct {
 	RepoInfo          RepoInfo      `json:"repo"`               // 仓库
 	Release           Release       `json:"release"`            // 发行版
 	Files             TemplateFiles `json:"files"`              // 文件
+	Content           Content       `json:"content"`            // package.zip 内容
+	Secrets           Secrets       `json:"secrets"`            // 凭据泄露扫描
+	License           License       `json:"license"`            // 许可证
 	Attrs             Attrs         `json:"attrs"`              // 属性
 	MaintainerChanged bool          `json:"maintainer_changed"` // 更换了维护者
 }
@@ -90,6 +101,9 @@ type Theme struct {
 	RepoInfo          RepoInfo   `json:"repo"`               // 仓库
 	Release           Release    `json:"release"`            // 发行版
 	Files             ThemeFiles `json:"files"`              // 文件
+	Content           Content    `json:"content"`            // package.zip 内容
+	Secrets           Secrets    `json:"secrets"`            // 凭据泄露扫描
+	License           License    `json:"license"`            // 许可证
 	Attrs             Attrs      `json:"attrs"`              // 属性
 	MaintainerChanged bool       `json:"maintainer_changed"` // 更换了维护者
 }
@@ -108,6 +122,9 @@ type Widget struct {
 	RepoInfo          RepoInfo    `json:"repo"`               // 仓库
 	Release           Release     `json:"release"`            // 发行版
 	Files             WidgetFiles `json:"files"`              // 文件
+	Content           Content     `json:"content"`            // package.zip 内容
+	Secrets           Secrets     `json:"secrets"`            // 凭据泄露扫描
+	License           License     `json:"license"`            // 许可证
 	Attrs             Attrs       `json:"attrs"`              // 属性
 	MaintainerChanged bool        `json:"maintainer_changed"` // 更换了维护者
 }
@@ -123,10 +140,25 @@ type WidgetFiles struct {
 
 // RepoInfo 仓库信息
 type RepoInfo struct {
-	Owner string `json:"owner"` // 仓库拥有者
-	Name  string `json:"name"`  // 仓库名
-	Path  string `json:"path"`  // 仓库路径
-	Home  string `json:"home"`  // 仓库主页
+	Owner  string `json:"owner"`  // 仓库拥有者
+	Name   string `json:"name"`   // 仓库名
+	Path   string `json:"path"`   // 仓库路径
+	Home   string `json:"home"`   // 仓库主页
+	Health Health `json:"health"` // 仓库状态
+}
+
+// Health 仓库状态检查结果
+type Health struct {
+	Pass  bool          `json:"pass"`  // 是否通过所有检查项
+	Error string        `json:"error"` // 获取仓库信息失败的原因
+	Items []*HealthItem `json:"items"` // 检查项
+}
+
+// HealthItem 仓库状态检查项
+type HealthItem struct {
+	Name   string `json:"name"`   // 检查项名称
+	Pass   bool   `json:"pass"`   // 是否通过
+	Detail string `json:"detail"` // 未通过时的说明
 }
 
 // Release 发行版
@@ -150,6 +182,40 @@ type PackageZip struct {
 	URL  string `json:"url"`  // package.zip 包 URL
 }
 
+// Content package.zip 中按类型必须包含的内容
+type Content struct {
+	Pass  bool                 `json:"pass"`  // 是否满足所有规则
+	Error string               `json:"error"` // package.zip 下载或解析失败的原因
+	Rules []*model.ContentRule `json:"rules"` // 规则检查结果
+
+	Syntax *model.JSSyntaxError `json:"syntax"` // 插件 index.js、图标 icon.js 的语法错误
+	CSS    []*model.CSSIssue    `json:"css"`    // 主题 theme.css 及其导入文件的问题，warning 不影响 Pass
+
+	Security []*model.SecurityFinding `json:"security"` // 插件 JavaScript 安全扫描发现，供审核参考，不影响 Pass
+}
+
+// Secrets package.zip 及发行版提交时仓库文件的凭据泄露扫描结果
+type Secrets struct {
+	Pass     bool                   `json:"pass"`     // 是否未发现凭据
+	Error    string                 `json:"error"`    // 仓库文件列表获取失败的原因
+	Findings []*model.SecretFinding `json:"findings"` // 打码后的发现
+}
+
+// License 仓库及 package.zip 中许可证文件的识别结果
+type License struct {
+	Pass  bool           `json:"pass"`  // 是否识别出许可证
+	ID    string         `json:"id"`    // SPDX 标识符，优先取仓库中的许可证
+	Files []*LicenseFile `json:"files"` // 找到的许可证文件
+}
+
+// LicenseFile 许可证文件
+type LicenseFile struct {
+	Source string `json:"source"` // repository 或 package.zip
+	File   string `json:"file"`   // 文件路径
+	URL    string `json:"url"`    // 仓库中文件的预览地址，package.zip 中的文件为空
+	ID     string `json:"id"`     // 识别出的 SPDX 标识符，无法识别时为空
+}
+
 // File 文件
 type File struct {
 	Pass bool `json:"pass"` // 文件是否存在
@@ -165,6 +231,11 @@ type Attrs struct {
 	Version Attr `json:"version"`
 	Author  Attr `json:"author"`
 	URL     Attr `json:"url"`
+
+	Readme Readme `json:"readme"`
+	Enums  Enums  `json:"enums"`
+
+	Keywords Keywords `json:"keywords"` // 仅供参考，不影响检查是否通过
 }
 
 type Name struct {
@@ -176,6 +247,32 @@ type Name struct {
 	Unique bool `json:"unique"` // name 字段值在所有类型的包中是否唯一 (大小写不敏感)
 }
 
+// Readme 清单 readme 字段声明的多语言 README 文件
+type Readme struct {
+	Pass  bool          `json:"pass"`  // 声明的 README 文件是否均存在于发行版对应的提交中
+	Files []*ReadmeFile `json:"files"` // 按 locale 排序
+}
+
+// ReadmeFile 某个 locale 声明的 README 文件
+type ReadmeFile struct {
+	File
+
+	Locale string `json:"locale"` // locale，如 default、zh_CN
+	Path   string `json:"path"`   // 声明的路径，合法时为规范化后的路径
+}
+
+// Enums 插件 backends、frontends 及主题 modes 字段的取值检查
+type Enums struct {
+	Pass    bool                 `json:"pass"`    // 取值是否均合法
+	Invalid []*model.InvalidEnum `json:"invalid"` // 不合法的取值
+}
+
+// Keywords 清单 keywords 字段按关键词分类表规范化的结果
+type Keywords struct {
+	Normalized []*model.KeywordChange `json:"normalized"` // 规范化后发生变化的关键词
+	Unknown    []string               `json:"unknown"`    // 不在分类表中的关键词
+}
+
 type Attr struct {
 	Pass  bool   `json:"pass"`  // 配置文件属性检查是否通过
 	Value string `json:"value"` // 配置文件属性值
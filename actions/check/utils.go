@@ -12,8 +12,11 @@ package main
 
 import (
 	"fmt"
+	"path"
 	"regexp"
 	"strings"
+
+	"github.com/siyuan-note/bazaar/actions/model"
 )
 
 var (
@@ -46,6 +49,24 @@ var (
 	}
 )
 
+// resourceTypeName 返回资源类型对应的包类型名称（与 *.txt、stage/*.json 文件名一致）
+func resourceTypeName(resourceType ResourceType) string {
+	switch resourceType {
+	case icons:
+		return model.TypeIcons
+	case plugins:
+		return model.TypePlugins
+	case templates:
+		return model.TypeTemplates
+	case themes:
+		return model.TypeThemes
+	case widgets:
+		return model.TypeWidgets
+	default:
+		panic("resourceTypeName: invalid resource type")
+	}
+}
+
 // isNameInBuiltinList 判断 name 是否在内置包名列表中（不区分大小写）
 func isNameInBuiltinList(name string, list []string) bool {
 	nameLower := strings.ToLower(name)
@@ -130,3 +151,14 @@ func buildRepoHomeURL(
 ) string {
 	return fmt.Sprintf("https://github.com/%s/%s", repoOwner, repoName)
 }
+
+// isSecretScanCandidate 判断仓库文件是否需要下载内容扫描凭据：跳过依赖目录、二进制格式与过大的文件
+func isSecretScanCandidate(filePath string, size int) bool {
+	if size > model.MaxSecretScanFileSize {
+		return false
+	}
+	if strings.HasPrefix(filePath, "node_modules/") || strings.Contains(filePath, "/node_modules/") {
+		return false
+	}
+	return !isKeyInSet(strings.ToLower(path.Ext(filePath)), SECRET_SCAN_SKIPPED_EXTS)
+}
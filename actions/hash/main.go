@@ -12,8 +12,6 @@ package main
 
 import (
 	"os"
-	"os/exec"
-	"strings"
 	"time"
 
 	"github.com/88250/gulu"
@@ -26,16 +24,16 @@ var logger = gulu.Log.NewLogger(os.Stdout)
 func main() {
 	logger.Infof("bazaar is hashing...")
 
-	cmd := exec.Command("git", "rev-parse", "HEAD")
-	data, err := cmd.CombinedOutput()
+	// 与 index 一致，bazaar hash 为最近一个修改了 stage 的提交
+	hashes, err := util.StageHashes(1)
 	if nil != err {
 		logger.Fatalf("get git hash failed: %s", err)
 	}
-	hash := strings.TrimSpace(string(data))
+	hash := hashes[0]
 	logger.Infof("bazaar [%s]", hash)
 
 	u := "https://rhythm.b3log.org/api/siyuan/bazaar/hash"
-	resp, data, errs := gorequest.New().Post(u).
+	resp, _, errs := gorequest.New().Post(u).
 		SendMap(map[string]interface{}{
 			"token": os.Getenv("RHYTHEM_TOKEN"),
 			"hash":  hash,
@@ -0,0 +1,68 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/siyuan-note/bazaar/actions/model"
+)
+
+// stageDeltas 计算上一个 bazaar hash（上一个修改了 stage 的提交）到当前 hash 的增量并上传，key 为 bazaar@<hash>/delta/<prevHash>/<type>.json。
+// 持有 prevHash 的客户端可以按增量更新，持有其他 hash 的客户端请求不到增量时回退为下载完整的 stage 文件。
+func stageDeltas(prevHash, hash string) {
+	if "" == prevHash {
+		logger.Warnf("previous stage commit not found, skip delta")
+		return
+	}
+	logger.Infof("delta [%s] -> [%s]", prevHash, hash)
+
+	for _, typ := range model.Types {
+		stageDelta(prevHash, hash, typ)
+	}
+}
+
+func stageDelta(prevHash, hash, typ string) {
+	_, oldData, err := getStageIndex(prevHash, typ)
+	if nil != err {
+		logger.Warnf("skip delta [%s]: %s", typ, err)
+		return
+	}
+	oldFile, err := model.ParseStageFile(oldData, typ)
+	if nil != err {
+		logger.Warnf("skip delta [%s]: parse previous stage failed: %s", typ, err)
+		return
+	}
+	u, newData, err := getStageIndex(hash, typ)
+	if nil != err {
+		logger.Fatalf("%s", err)
+		return
+	}
+	newFile, err := model.ParseStageFile(newData, typ)
+	if nil != err {
+		logger.Fatalf("unmarshal [%s] failed: %s", u, err)
+		return
+	}
+
+	delta, err := model.ComputeDelta(prevHash, hash, typ, oldFile, newFile)
+	if nil != err {
+		logger.Fatalf("compute delta [%s] failed: %s", typ, err)
+		return
+	}
+	data, err := json.Marshal(delta)
+	if nil != err {
+		logger.Fatalf("marshal delta [%s] failed: %s", typ, err)
+		return
+	}
+
+	logger.Infof("delta [%s]: added %d, updated %d, removed %d", typ, len(delta.Added), len(delta.Updated), len(delta.Removed))
+	uploadIndex("bazaar@"+hash+"/delta/"+prevHash+"/"+typ+".json", "delta/"+typ, data)
+}
@@ -12,13 +12,14 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 	"time"
 
 	"github.com/88250/gulu"
 	"github.com/parnurzeal/gorequest"
+	"github.com/siyuan-note/bazaar/actions/model"
 	"github.com/siyuan-note/bazaar/actions/util"
 )
 
@@ -27,53 +28,147 @@ var logger = gulu.Log.NewLogger(os.Stdout)
 func main() {
 	logger.Infof("bazaar is indexing...")
 
-	cmd := exec.Command("git", "rev-parse", "HEAD")
-	data, err := cmd.CombinedOutput()
+	hashes, err := util.StageHashes(2)
 	if nil != err {
 		logger.Fatalf("get git hash failed: %s", err)
 	}
-	hash := strings.TrimSpace(string(data))
+	hash, prevHash := hashes[0], ""
+	if 1 < len(hashes) {
+		prevHash = hashes[1]
+	}
 	logger.Infof("bazaar [%s]", hash)
 
-	stageIndex(hash, "themes")
-	stageIndex(hash, "templates")
-	stageIndex(hash, "icons")
-	stageIndex(hash, "widgets")
-	stageIndex(hash, "plugins")
+	for _, typ := range model.Types {
+		stageIndex(hash, typ)
+	}
+	stageIndex(hash, model.CatalogName)
+	stageIndex(hash, model.AuthorsName)
+	stageIndex(hash, model.TagsName)
+	stageIndex(hash, model.CompatName)
+	for _, locale := range model.Locales {
+		stageIndex(hash, model.SearchDir+"/"+locale)
+	}
+	stageDeltas(prevHash, hash)
+
+	logCompressionReport()
 
 	logger.Infof("indexed bazaar")
 }
 
-func stageIndex(hash string, index string) {
-	u := "https://raw.githubusercontent.com/siyuan-note/bazaar/" + hash + "/stage/" + index + ".json"
+// getStageIndex 获取指定 bazaar hash 下的 stage/<index>.json
+func getStageIndex(hash string, index string) (u string, data []byte, err error) {
+	u = "https://raw.githubusercontent.com/siyuan-note/bazaar/" + hash + "/stage/" + index + ".json"
 	resp, data, errs := gorequest.New().Get(u).
 		Set("User-Agent", util.UserAgent).
 		Retry(1, 3*time.Second).Timeout(30 * time.Second).EndBytes()
 	if nil != errs {
-		logger.Fatalf("get [%s] failed: %s", u, errs)
+		err = fmt.Errorf("get [%s] failed: %s", u, errs)
 		return
 	}
 	if 200 != resp.StatusCode {
-		logger.Fatalf("get [%s] failed: %d", u, resp.StatusCode)
+		err = fmt.Errorf("get [%s] failed: %d", u, resp.StatusCode)
+		return
+	}
+	return
+}
+
+func stageIndex(hash string, index string) {
+	u, data, err := getStageIndex(hash, index)
+	if nil != err {
+		logger.Fatalf("%s", err)
 		return
 	}
 
-	// 压缩 JSON：解析后重新序列化为压缩格式（移除空格和换行）
-	var jsonData interface{}
-	err := json.Unmarshal(data, &jsonData)
+	// 压缩 JSON：按类型解析后重新序列化为压缩格式（移除空格和换行）
+	var parsed interface{}
+	switch {
+	case model.CatalogName == index:
+		parsed, err = model.ParseCatalog(data)
+	case model.AuthorsName == index:
+		parsed = &model.AuthorsIndex{}
+		err = json.Unmarshal(data, parsed)
+	case model.TagsName == index:
+		parsed = &model.TagsIndex{}
+		err = json.Unmarshal(data, parsed)
+	case model.CompatName == index:
+		parsed = &model.CompatIndex{}
+		err = json.Unmarshal(data, parsed)
+	case strings.HasPrefix(index, model.SearchDir+"/"):
+		parsed = &model.SearchIndex{}
+		err = json.Unmarshal(data, parsed)
+	default:
+		parsed, err = model.ParseStageFile(data, index)
+	}
 	if nil != err {
 		logger.Fatalf("unmarshal [%s] failed: %s", u, err)
 		return
 	}
-	data, err = json.Marshal(jsonData)
+	data, err = json.Marshal(parsed)
 	if nil != err {
 		logger.Fatalf("marshal [%s] failed: %s", u, err)
 		return
 	}
 
-	key := "bazaar@" + hash + "/stage/" + index + ".json"
-	err = util.UploadOSS(key, "application/json", data)
+	uploadIndex("bazaar@"+hash+"/stage/"+index+".json", index, data)
+}
+
+// uploadIndex 上传索引文件及其预压缩版本，name 用于压缩统计
+func uploadIndex(key, name string, data []byte) {
+	err := util.UploadOSS(key, "application/json", data)
 	if nil != err {
 		logger.Fatalf("upload bazaar stage index [%s] failed: %s", key, err)
 	}
+
+	// 上传预压缩版本，key 为原 key 加编码后缀，如 stage/plugins.json.br，Content-Type 仍为 application/json 并带有 Content-Encoding
+	sizes := map[string]int{}
+	for _, encoding := range util.Encodings {
+		compressed, compressErr := util.Compress(encoding.Name, data)
+		if nil != compressErr {
+			logger.Fatalf("compress [%s] with [%s] failed: %s", key, encoding.Name, compressErr)
+			return
+		}
+		if err = util.UploadOSSEncoded(key+encoding.Ext, "application/json", encoding.Name, compressed); nil != err {
+			logger.Fatalf("upload bazaar stage index [%s] failed: %s", key+encoding.Ext, err)
+		}
+		sizes[encoding.Name] = len(compressed)
+	}
+	compressionReport = append(compressionReport, &compressionStat{index: name, size: len(data), sizes: sizes})
+}
+
+// compressionStat 单个索引文件的预压缩大小统计
+type compressionStat struct {
+	index string
+	size  int
+	sizes map[string]int // 编码 -> 压缩后大小
+}
+
+var compressionReport []*compressionStat
+
+// logCompressionReport 输出各索引文件预压缩后的大小及节省比例
+func logCompressionReport() {
+	total := 0
+	totals := map[string]int{}
+	for _, stat := range compressionReport {
+		total += stat.size
+		line := fmt.Sprintf("[%s] %d bytes", stat.index, stat.size)
+		for _, encoding := range util.Encodings {
+			size := stat.sizes[encoding.Name]
+			totals[encoding.Name] += size
+			line += fmt.Sprintf(", %s %d (-%.1f%%)", encoding.Name, size, savedPercent(stat.size, size))
+		}
+		logger.Infof("%s", line)
+	}
+
+	line := fmt.Sprintf("[total] %d bytes", total)
+	for _, encoding := range util.Encodings {
+		line += fmt.Sprintf(", %s %d (-%.1f%%)", encoding.Name, totals[encoding.Name], savedPercent(total, totals[encoding.Name]))
+	}
+	logger.Infof("%s", line)
+}
+
+func savedPercent(size, compressed int) float64 {
+	if 0 == size {
+		return 0
+	}
+	return float64(size-compressed) * 100 / float64(size)
 }
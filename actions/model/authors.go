@@ -0,0 +1,54 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package model
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// AuthorsName 作者索引的文件名（不含扩展名），即 stage/authors.json
+const AuthorsName = "authors"
+
+// AuthorPackage 作者名下的包
+type AuthorPackage struct {
+	Type  string `json:"type"`  // 包类型
+	Repo  string `json:"repo"`  // owner/repo
+	Name  string `json:"name"`  // package.name
+	Stars int    `json:"stars"` // star 数
+}
+
+// Author 按 GitHub owner 汇总的作者
+type Author struct {
+	Owner    string           `json:"owner"`    // GitHub 仓库 owner
+	Names    []string         `json:"names"`    // 清单中出现过的 author 字段值
+	Stars    int              `json:"stars"`    // 所有包的 star 总数
+	Total    int              `json:"total"`    // 包总数
+	Counts   map[string]int   `json:"counts"`   // 各类型包数
+	Funding  []string         `json:"funding"`  // 赞助链接（去重）
+	Updated  string           `json:"updated"`  // 最近一次更新时间
+	Packages []*AuthorPackage `json:"packages"` // 包列表，按类型、owner/repo 排序
+}
+
+// AuthorsIndex 对应 stage/authors.json
+type AuthorsIndex struct {
+	Authors []*Author           `json:"authors"` // 按 owner 排序（不区分大小写）
+	Names   map[string][]string `json:"names"`   // 清单 author 字段值 -> GitHub owner 列表，便于发现同一作者在不同 owner 下的包
+}
+
+// SaveAuthorsIndex 以两空格缩进格式写入作者索引
+func SaveAuthorsIndex(filePath string, index *AuthorsIndex) error {
+	data, err := json.MarshalIndent(index, "", "  ")
+	if nil != err {
+		return err
+	}
+	return os.WriteFile(filePath, data, 0644)
+}
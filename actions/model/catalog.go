@@ -0,0 +1,87 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// CatalogName 跨类型汇总索引的文件名（不含扩展名），即 stage/bazaar.json
+const CatalogName = "bazaar"
+
+// CatalogSchemaVersion 汇总索引的格式版本，格式不兼容变更时递增
+const CatalogSchemaVersion = 1
+
+// CatalogRepo 汇总索引中的包，在 StageRepo 基础上标注包类型
+type CatalogRepo struct {
+	Type string `json:"type"` // 包类型，如 plugins、themes
+	*StageRepo
+}
+
+// Catalog 对应 stage/bazaar.json，汇总所有类型的包，客户端一次请求即可构建全局搜索
+type Catalog struct {
+	SchemaVersion int            `json:"schemaVersion"` // 格式版本
+	Generator     string         `json:"generator"`     // 生成者
+	Generated     string         `json:"generated"`     // 生成时间（RFC 3339），包数据无变化时保持不变
+	Total         int            `json:"total"`         // 包总数
+	Counts        map[string]int `json:"counts"`        // 各类型包数
+	Repos         []*CatalogRepo `json:"repos"`
+}
+
+// ParseCatalog 解析汇总索引内容，package 字段按各条目的 type 解析为对应类型的 Manifest
+func ParseCatalog(data []byte) (ret *Catalog, err error) {
+	raw := struct {
+		Catalog
+		Repos []*struct {
+			Type string `json:"type"`
+			rawStageRepo
+		} `json:"repos"`
+	}{}
+	if err = json.Unmarshal(data, &raw); nil != err {
+		return
+	}
+
+	ret = &raw.Catalog
+	ret.Repos = make([]*CatalogRepo, 0, len(raw.Repos))
+	for i, r := range raw.Repos {
+		if nil == r {
+			continue
+		}
+		repo := r.StageRepo
+		if 0 < len(r.Package) && "null" != string(r.Package) {
+			if repo.Package, err = ParseManifest(r.Package, r.Type); nil != err {
+				return nil, fmt.Errorf("repo #%d [%s]: %w", i, repo.URL, err)
+			}
+		}
+		ret.Repos = append(ret.Repos, &CatalogRepo{Type: r.Type, StageRepo: &repo})
+	}
+	return
+}
+
+// LoadCatalog 读取并解析汇总索引
+func LoadCatalog(filePath string) (*Catalog, error) {
+	data, err := os.ReadFile(filePath)
+	if nil != err {
+		return nil, err
+	}
+	return ParseCatalog(data)
+}
+
+// SaveCatalog 以两空格缩进格式写入汇总索引
+func SaveCatalog(filePath string, catalog *Catalog) error {
+	data, err := json.MarshalIndent(catalog, "", "  ")
+	if nil != err {
+		return err
+	}
+	return os.WriteFile(filePath, data, 0644)
+}
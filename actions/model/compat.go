@@ -0,0 +1,121 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package model
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// CompatName 兼容性索引的文件名（不含扩展名），即 stage/compat.json
+const CompatName = "compat"
+
+// CompatReleaseLimit 每个包保留的历史发布版本数
+const CompatReleaseLimit = 30
+
+// CompatRelease 包的一个发布版本
+type CompatRelease struct {
+	Version       string `json:"version"`       // 包版本
+	Hash          string `json:"hash"`          // 发布版本对应的提交 hash
+	MinAppVersion string `json:"minAppVersion"` // 最低思源版本，为空表示不限
+	MaxAppVersion string `json:"maxAppVersion"` // 最高思源版本，为空表示不限
+	Updated       string `json:"updated"`       // 发布时间
+}
+
+// CompatPackage 包的发布历史及兼容性视图
+type CompatPackage struct {
+	Type       string            `json:"type"`       // 包类型
+	Releases   []*CompatRelease  `json:"releases"`   // stage 期间见到的发布版本，按发布时间降序
+	Compatible map[string]string `json:"compatible"` // 思源 minor 版本（如 2.10）-> 兼容的最新包版本
+}
+
+// CompatIndex 对应 stage/compat.json。发布历史由每次 stage 见到的最新发布版本累积而来。
+// AppMinors 覆盖到 stage 时思源的最新发布版本；客户端版本不在其中时（如刚发布的新版本），
+// 应使用不大于自身版本的最大 minor 查询 Compatible
+type CompatIndex struct {
+	AppMinors []string                  `json:"appMinors"` // 覆盖的思源 minor 版本，升序
+	Packages  map[string]*CompatPackage `json:"packages"`  // owner/repo -> 包
+}
+
+// LoadCompatIndex 读取兼容性索引，文件不存在时返回空索引
+func LoadCompatIndex(filePath string) (ret *CompatIndex, err error) {
+	ret = &CompatIndex{Packages: map[string]*CompatPackage{}}
+	data, err := os.ReadFile(filePath)
+	if nil != err {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return
+	}
+	if err = json.Unmarshal(data, ret); nil != err {
+		return
+	}
+	if nil == ret.Packages {
+		ret.Packages = map[string]*CompatPackage{}
+	}
+	return
+}
+
+// SaveCompatIndex 以两空格缩进格式写入兼容性索引
+func SaveCompatIndex(filePath string, index *CompatIndex) error {
+	data, err := json.MarshalIndent(index, "", "  ")
+	if nil != err {
+		return err
+	}
+	return os.WriteFile(filePath, data, 0644)
+}
+
+// Supports 判断发布版本是否支持思源 minor 版本 appMinor（major.minor 形式），
+// minAppVersion 的 minor 不高于 appMinor 且 maxAppVersion 的 minor 不低于 appMinor 即视为支持；版本号不合法时返回 false
+func (r *CompatRelease) Supports(appMinor Version) bool {
+	if "" != r.MinAppVersion {
+		minVer, ok := ParseVersion(r.MinAppVersion)
+		if !ok || 0 < compareMinor(minVer, appMinor) {
+			return false
+		}
+	}
+	if "" != r.MaxAppVersion {
+		maxVer, ok := ParseVersion(r.MaxAppVersion)
+		if !ok || 0 > compareMinor(maxVer, appMinor) {
+			return false
+		}
+	}
+	return true
+}
+
+// compareMinor 只比较 major.minor
+func compareMinor(a, b Version) int {
+	return Version{Major: a.Major, Minor: a.Minor}.Compare(Version{Major: b.Major, Minor: b.Minor})
+}
+
+// CompatibleVersion 返回包 repo（owner/repo）兼容思源版本 appVersion 的最新包版本：使用 AppMinors 中不大于 appVersion 的最大 minor 查询，
+// 包不存在、版本号不合法或没有兼容版本时返回空字符串
+func (index *CompatIndex) CompatibleVersion(repo, appVersion string) string {
+	compatPkg := index.Packages[repo]
+	v, ok := ParseVersion(appVersion)
+	if nil == compatPkg || !ok {
+		return ""
+	}
+	floor := ""
+	for _, appMinor := range index.AppMinors {
+		minorVer, minorOk := ParseVersion(appMinor)
+		if !minorOk || 0 < compareMinor(minorVer, v) {
+			continue
+		}
+		if floorVer, _ := ParseVersion(floor); "" == floor || 0 < compareMinor(minorVer, floorVer) {
+			floor = appMinor
+		}
+	}
+	if "" == floor {
+		return ""
+	}
+	return compatPkg.Compatible[floor]
+}
@@ -0,0 +1,66 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// Delta 两个 bazaar hash 之间某类型 stage 文件的增量，以 owner/repo 为粒度。
+// 客户端持有 From 对应的 stage 文件时，按 owner/repo 删除 Removed、用 Added/Updated 覆盖，再按 updated 降序排序即得到 To 对应的 stage 文件。
+type Delta struct {
+	From    string       `json:"from"`    // 旧 bazaar hash
+	To      string       `json:"to"`      // 新 bazaar hash
+	Type    string       `json:"type"`    // 包类型
+	Added   []*StageRepo `json:"added"`   // 新增的包
+	Updated []*StageRepo `json:"updated"` // 数据有变化的包（完整条目）
+	Removed []string     `json:"removed"` // 被移除的包 owner/repo
+}
+
+// ComputeDelta 计算从 oldFile 到 newFile 的增量，条目序列化结果不同即视为更新
+func ComputeDelta(from, to, typ string, oldFile, newFile *StageFile) (ret *Delta, err error) {
+	ret = &Delta{From: from, To: to, Type: typ, Added: []*StageRepo{}, Updated: []*StageRepo{}, Removed: []string{}}
+	oldRepos := oldFile.RepoMap()
+	newRepos := newFile.RepoMap()
+
+	for _, repo := range newFile.Repos {
+		key := repo.RepoPath()
+		if "" == key {
+			continue
+		}
+		oldRepo, exists := oldRepos[key]
+		if !exists {
+			ret.Added = append(ret.Added, repo)
+			continue
+		}
+
+		var oldData, newData []byte
+		if oldData, err = json.Marshal(oldRepo); nil != err {
+			return nil, err
+		}
+		if newData, err = json.Marshal(repo); nil != err {
+			return nil, err
+		}
+		if !bytes.Equal(oldData, newData) {
+			ret.Updated = append(ret.Updated, repo)
+		}
+	}
+
+	for key := range oldRepos {
+		if _, exists := newRepos[key]; !exists {
+			ret.Removed = append(ret.Removed, key)
+		}
+	}
+	sort.Strings(ret.Removed)
+	return
+}
@@ -0,0 +1,85 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComputeDelta(t *testing.T) {
+	tests := []struct {
+		name        string
+		oldRepos    []*StageRepo
+		newRepos    []*StageRepo
+		wantAdded   []string
+		wantUpdated []string
+		wantRemoved []string
+	}{
+		{
+			name:     "unchanged",
+			oldRepos: []*StageRepo{{URL: "a/b@1", Stars: 1}},
+			newRepos: []*StageRepo{{URL: "a/b@1", Stars: 1}},
+		},
+		{
+			name:      "added",
+			oldRepos:  []*StageRepo{{URL: "a/b@1"}},
+			newRepos:  []*StageRepo{{URL: "c/d@1"}, {URL: "a/b@1"}},
+			wantAdded: []string{"c/d@1"},
+		},
+		{
+			name:        "removed",
+			oldRepos:    []*StageRepo{{URL: "e/f@1"}, {URL: "a/b@1"}, {URL: "c/d@1"}},
+			newRepos:    []*StageRepo{{URL: "a/b@1"}},
+			wantRemoved: []string{"c/d", "e/f"},
+		},
+		{
+			name:        "changed",
+			oldRepos:    []*StageRepo{{URL: "a/b@1", Stars: 1}, {URL: "c/d@1"}, {URL: "e/f@1", License: "MIT"}},
+			newRepos:    []*StageRepo{{URL: "a/b@1", Stars: 2}, {URL: "c/d@2"}, {URL: "e/f@1", License: "MIT"}},
+			wantUpdated: []string{"a/b@1", "c/d@2"},
+		},
+		{
+			name:        "added, changed and removed",
+			oldRepos:    []*StageRepo{{URL: "a/b@1"}, {URL: "c/d@1"}},
+			newRepos:    []*StageRepo{{URL: "x/y@1"}, {URL: "c/d@1", Archived: true}, {URL: "invalid"}},
+			wantAdded:   []string{"x/y@1"},
+			wantUpdated: []string{"c/d@1"},
+			wantRemoved: []string{"a/b"},
+		},
+	}
+	urls := func(repos []*StageRepo) (ret []string) {
+		for _, repo := range repos {
+			ret = append(ret, repo.URL)
+		}
+		return
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delta, err := ComputeDelta("h1", "h2", TypePlugins, &StageFile{Repos: tt.oldRepos}, &StageFile{Repos: tt.newRepos})
+			if nil != err {
+				t.Fatal(err)
+			}
+			if "h1" != delta.From || "h2" != delta.To || TypePlugins != delta.Type {
+				t.Errorf("ComputeDelta() header = %s %s %s", delta.From, delta.To, delta.Type)
+			}
+			if got := urls(delta.Added); !reflect.DeepEqual(got, tt.wantAdded) {
+				t.Errorf("Added = %v, want %v", got, tt.wantAdded)
+			}
+			if got := urls(delta.Updated); !reflect.DeepEqual(got, tt.wantUpdated) {
+				t.Errorf("Updated = %v, want %v", got, tt.wantUpdated)
+			}
+			if got := delta.Removed; (0 != len(got) || 0 != len(tt.wantRemoved)) && !reflect.DeepEqual(got, tt.wantRemoved) {
+				t.Errorf("Removed = %v, want %v", got, tt.wantRemoved)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package model
+
+import "slices"
+
+// 插件 backends、frontends 及主题 modes 字段的合法取值
+var (
+	PluginBackends  = []string{"windows", "linux", "darwin", "docker", "ios", "android", "harmony", "all"}
+	PluginFrontends = []string{"desktop", "desktop-window", "browser-desktop", "mobile", "browser-mobile", "all"}
+	ThemeModes      = []string{"light", "dark"}
+)
+
+// InvalidEnum 取值受限的字段中不合法的取值
+type InvalidEnum struct {
+	Field   string   `json:"field"`   // 字段名，如 backends
+	Values  []string `json:"values"`  // 不合法的取值
+	Allowed []string `json:"allowed"` // 合法取值
+}
+
+// ValidateEnums 校验插件 backends、frontends 及主题 modes 字段的取值，返回不合法的取值；drop 为 true 时从清单中移除这些取值
+func ValidateEnums(manifest Manifest, drop bool) (ret []*InvalidEnum) {
+	validate := func(field string, values *[]string, allowed []string) {
+		var valid, invalid []string
+		for _, value := range *values {
+			if slices.Contains(allowed, value) {
+				valid = append(valid, value)
+			} else if !slices.Contains(invalid, value) {
+				invalid = append(invalid, value)
+			}
+		}
+		if 0 == len(invalid) {
+			return
+		}
+		ret = append(ret, &InvalidEnum{Field: field, Values: invalid, Allowed: allowed})
+		if drop {
+			*values = valid
+		}
+	}
+
+	switch m := manifest.(type) {
+	case *PluginPackage:
+		validate("backends", &m.Backends, PluginBackends)
+		validate("frontends", &m.Frontends, PluginFrontends)
+	case *ThemePackage:
+		validate("modes", &m.Modes, ThemeModes)
+	}
+	return
+}
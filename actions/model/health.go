@@ -0,0 +1,54 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package model
+
+// RepoHealth GitHub 仓库的状态，字段与 GET /repos/{owner}/{repo} 的返回一致
+type RepoHealth struct {
+	Archived   bool   `json:"archived"`   // 已归档，只读
+	Disabled   bool   `json:"disabled"`   // 已被 GitHub 禁用
+	Private    bool   `json:"private"`    // 私有仓库
+	Fork       bool   `json:"fork"`       // 派生仓库
+	Visibility string `json:"visibility"` // public、private 或 internal，旧接口可能为空
+}
+
+// Public 判断仓库是否公开
+func (health *RepoHealth) Public() bool {
+	return !health.Private && ("" == health.Visibility || "public" == health.Visibility)
+}
+
+// ExcludeReason 返回仓库不能上架的原因：被禁用或非公开，可以上架时返回空字符串
+func (health *RepoHealth) ExcludeReason() string {
+	if health.Disabled {
+		return "repository is disabled"
+	}
+	if !health.Public() {
+		visibility := health.Visibility
+		if "" == visibility {
+			visibility = "private"
+		}
+		return "repository is not public (" + visibility + ")"
+	}
+	return ""
+}
+
+// RepoUnavailableReason 返回 GET /repos/{owner}/{repo} 的状态码表明仓库不可访问的原因：
+// 404 为不存在或已转为私有，403 为被禁用，451 为因法律原因被屏蔽；其他状态码返回空字符串
+func RepoUnavailableReason(statusCode int) string {
+	switch statusCode {
+	case 404:
+		return "repository not found or not public"
+	case 403:
+		return "repository access blocked"
+	case 451:
+		return "repository unavailable for legal reasons"
+	}
+	return ""
+}
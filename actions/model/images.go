@@ -0,0 +1,46 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package model
+
+import (
+	"path"
+	"strings"
+)
+
+// 图片变体格式
+const (
+	ImageFormatPNG  = "png"
+	ImageFormatWebP = "webp"
+)
+
+// ImageVariant 图片的一个变体，上传到 package/<owner>/<repo>@<hash>/<Path>
+type ImageVariant struct {
+	Path   string `json:"path"`   // 相对于包目录的路径，如 preview-512x384.webp
+	Width  int    `json:"width"`  // 宽度（像素）
+	Height int    `json:"height"` // 高度（像素）
+	Format string `json:"format"` // png 或 webp
+}
+
+// Images 预览图及图标的变体，原图不在其中
+type Images struct {
+	Preview []*ImageVariant `json:"preview,omitempty"`
+	Icon    []*ImageVariant `json:"icon,omitempty"`
+}
+
+// ImageVariantPath 返回变体的确定性路径，box 为缩略图的限定尺寸（如 512x384），为空表示原尺寸：
+// preview.png 的变体为 preview.webp、preview-512x384.png、preview-512x384.webp
+func ImageVariantPath(original, box, format string) string {
+	name := strings.TrimSuffix(original, path.Ext(original))
+	if "" != box {
+		name += "-" + box
+	}
+	return name + "." + format
+}
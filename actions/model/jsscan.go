@@ -0,0 +1,162 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package model
+
+import (
+	"bytes"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 安全扫描发现的严重程度，按风险从高到低
+const (
+	SecurityHigh   = "high"
+	SecurityMedium = "medium"
+	SecurityLow    = "low"
+)
+
+// maxScannedJSFiles 单个包中安全扫描的 JavaScript 文件数上限
+const maxScannedJSFiles = 256
+
+// maxEvidenceLen 发现中引用源码片段的长度上限
+const maxEvidenceLen = 80
+
+// securityRule 一条基于正则的安全扫描规则，文件中匹配次数不少于 minCount 时报告
+type securityRule struct {
+	id       string
+	severity string
+	message  string
+	pattern  *regexp.Regexp
+	minCount int
+	redact   bool // 源码片段中可能含有凭据，需打码后引用
+}
+
+// securityRules 插件代码安全扫描规则
+var securityRules = []*securityRule{
+	{id: "eval-encoded", severity: SecurityHigh, message: "evaluates decoded code",
+		pattern: regexp.MustCompile(`\beval\s*\(\s*(?:atob|unescape|decodeURIComponent|String\.fromCharCode)\s*\(`)},
+	{id: "eval", severity: SecurityMedium, message: "uses eval",
+		pattern: regexp.MustCompile(`(?:^|[^.\w$])eval\s*\(`)},
+	{id: "new-function", severity: SecurityMedium, message: "creates functions from strings with new Function",
+		pattern: regexp.MustCompile(`\bnew\s+Function\s*\(`)},
+	{id: "remote-script", severity: SecurityHigh, message: "injects or imports a remote script",
+		pattern: regexp.MustCompile(`(?i)<script[^>]+src\s*=\s*\\?["']?(?:https?:)?//|\.src\s*=\s*["'` + "`" + `](?:https?:)?//[^"'` + "`" + `]*\.m?js\b|\bimport\s*\(\s*["'` + "`" + `](?:https?:)?//|\bimportScripts\s*\(`)},
+	{id: "child-process", severity: SecurityHigh, message: "requires child_process and can run system commands",
+		pattern: regexp.MustCompile(`\brequire\s*\(\s*["'](?:node:)?child_process["']\s*\)`)},
+	{id: "fs", severity: SecurityMedium, message: "requires fs and can access files outside the workspace",
+		pattern: regexp.MustCompile(`\brequire\s*\(\s*["'](?:node:)?fs(?:/promises)?["']\s*\)`)},
+	{id: "obfuscated-identifiers", severity: SecurityMedium, message: "contains obfuscator-generated identifiers",
+		pattern: regexp.MustCompile(`\b_0x[0-9a-fA-F]{4,6}\b`), minCount: 50},
+	{id: "hex-escaped-string", severity: SecurityMedium, message: "contains a long hex-escaped string",
+		pattern: regexp.MustCompile(`(?:\\x[0-9a-fA-F]{2}){32,}`)},
+	{id: "encoded-blob", severity: SecurityLow, message: "contains a long encoded string literal",
+		pattern: regexp.MustCompile(`["'][A-Za-z0-9+/]{1000}[A-Za-z0-9+/]*={0,2}["']`)},
+	{id: "crypto-miner", severity: SecurityHigh, message: "contains crypto-miner signatures",
+		pattern: regexp.MustCompile(`(?i)\b(?:coinhive|coin-hive|cryptonight|coinimp|cryptoloot|webminerpool|deepminer|minero\.cc)\b|stratum\+(?:tcp|ssl)://`)},
+	{id: "hard-coded-token", severity: SecurityMedium, message: "contains a hard-coded credential",
+		pattern: regexp.MustCompile(`(?i)\b(?:api[_-]?key|api[_-]?secret|access[_-]?token|auth[_-]?token|client[_-]?secret|secret[_-]?key|password)["']?\s*[:=]\s*["'][A-Za-z0-9_\-.+/=]{16,}["']`), redact: true},
+}
+
+// SecurityFinding 安全扫描的一条发现，同一文件中同一规则的多次匹配合并为一条
+type SecurityFinding struct {
+	Rule     string `json:"rule"`     // 规则标识
+	Severity string `json:"severity"` // high、medium 或 low
+	File     string `json:"file"`     // 包内文件路径
+	Line     int    `json:"line"`     // 第一次匹配所在行号，从 1 开始
+	Count    int    `json:"count"`    // 匹配次数
+	Message  string `json:"message"`  // 发现描述
+	Evidence string `json:"evidence"` // 第一次匹配的源码片段，凭据已打码
+}
+
+// ScanJSSecurity 按规则扫描插件 package.zip 中的 JavaScript 文件，返回按严重程度排列的发现；其他类型不扫描
+func (pz *PackageZip) ScanJSSecurity(typ string) (ret []*SecurityFinding) {
+	if TypePlugins != typ {
+		return
+	}
+
+	scanned := 0
+	for _, name := range pz.Names() {
+		switch strings.ToLower(path.Ext(name)) {
+		case ".js", ".mjs", ".cjs":
+		default:
+			continue
+		}
+		if maxScannedJSFiles <= scanned {
+			break
+		}
+		scanned++
+
+		data, err := pz.ReadFile(name)
+		if nil != err {
+			continue
+		}
+		ret = append(ret, ScanJS(name, data)...)
+	}
+
+	rank := map[string]int{SecurityHigh: 0, SecurityMedium: 1, SecurityLow: 2}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return rank[ret[i].Severity] < rank[ret[j].Severity]
+	})
+	return
+}
+
+// ScanJS 按规则扫描单个 JavaScript 文件
+func ScanJS(file string, data []byte) (ret []*SecurityFinding) {
+	for _, rule := range securityRules {
+		matches := rule.pattern.FindAllIndex(data, -1)
+		if 0 == len(matches) || len(matches) < rule.minCount {
+			continue
+		}
+
+		first := matches[0]
+		// 合并空白与换行并替换反引号，便于在 Markdown 列表的行内代码中引用
+		evidence := strings.ReplaceAll(strings.Join(strings.Fields(string(data[first[0]:first[1]])), " "), "`", "'")
+		if rule.redact {
+			evidence = redactEvidence(evidence)
+		}
+		if runes := []rune(evidence); maxEvidenceLen < len(runes) {
+			evidence = string(runes[:maxEvidenceLen]) + "…"
+		}
+		ret = append(ret, &SecurityFinding{
+			Rule:     rule.id,
+			Severity: rule.severity,
+			File:     file,
+			Line:     bytes.Count(data[:first[0]], []byte("\n")) + 1,
+			Count:    len(matches),
+			Message:  rule.message,
+			Evidence: evidence,
+		})
+	}
+	return
+}
+
+// redactEvidence 打码片段末尾引号内的凭据值
+func redactEvidence(evidence string) string {
+	end := len(evidence) - 1
+	if 0 > end || ('"' != evidence[end] && '\'' != evidence[end]) {
+		return evidence
+	}
+	start := strings.LastIndexByte(evidence[:end], evidence[end])
+	if 0 > start {
+		return evidence
+	}
+	return evidence[:start+1] + RedactSecret(evidence[start+1:end]) + evidence[end:]
+}
+
+// RedactSecret 打码凭据，仅保留前 4 个字符
+func RedactSecret(secret string) string {
+	if 4 >= len(secret) {
+		return strings.Repeat("*", len(secret))
+	}
+	return secret[:4] + strings.Repeat("*", min(len(secret)-4, 16))
+}
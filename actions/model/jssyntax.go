@@ -0,0 +1,93 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package model
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/tdewolff/parse/v2"
+	"github.com/tdewolff/parse/v2/js"
+)
+
+// jsEntries 需要校验 JavaScript 语法的各类型入口文件
+var jsEntries = map[string]string{
+	TypePlugins: "index.js",
+	TypeIcons:   "icon.js",
+}
+
+// JSSyntaxError JavaScript 语法错误
+type JSSyntaxError struct {
+	File    string `json:"file"`    // 包内文件路径
+	Line    int    `json:"line"`    // 行号，从 1 开始，无法定位时为 0
+	Column  int    `json:"column"`  // 列号，从 1 开始，无法定位时为 0
+	Message string `json:"message"` // 错误信息
+}
+
+// CheckJSSyntax 解析类型对应的 JavaScript 入口文件（插件 index.js、图标 icon.js），返回语法错误；入口文件不存在时不检查
+func (pz *PackageZip) CheckJSSyntax(typ string) *JSSyntaxError {
+	entry, ok := jsEntries[typ]
+	if !ok || !pz.Has(entry) {
+		return nil
+	}
+
+	data, err := pz.ReadFile(entry)
+	if nil != err {
+		return &JSSyntaxError{File: entry, Message: err.Error()}
+	}
+	if TypePlugins == typ {
+		// 思源通过 new Function(...) 执行插件 index.js，顶层 return 等函数体中才合法的语法需要按函数体解析
+		return ParseJSFunctionBody(entry, data)
+	}
+	return ParseJS(entry, data)
+}
+
+// jsFunctionBodyPrefix、jsFunctionBodySuffix 按函数体解析时包裹源码，前缀独占一行，错误行号减一即为源码中的行号
+const (
+	jsFunctionBodyPrefix = "(function () {\n"
+	jsFunctionBodySuffix = "\n})"
+)
+
+// ParseJSFunctionBody 将 JavaScript 源码作为函数体解析，返回语法错误，行号、列号对应原始源码，无错误时返回 nil
+func ParseJSFunctionBody(file string, data []byte) *JSSyntaxError {
+	wrapped := make([]byte, 0, len(jsFunctionBodyPrefix)+len(data)+len(jsFunctionBodySuffix))
+	wrapped = append(wrapped, jsFunctionBodyPrefix...)
+	wrapped = append(wrapped, data...)
+	wrapped = append(wrapped, jsFunctionBodySuffix...)
+	ret := ParseJS(file, wrapped)
+	if nil == ret || 0 == ret.Line {
+		return ret
+	}
+
+	lines := bytes.Count(data, []byte("\n")) + 1
+	ret.Line--
+	if 1 > ret.Line {
+		ret.Line, ret.Column = 1, 1
+	} else if lines < ret.Line {
+		// 错误位于包裹的后缀（如缺少右括号），定位到源码末尾
+		ret.Line, ret.Message = lines, "unexpected end of input"
+		ret.Column = len([]rune(string(data[bytes.LastIndexByte(data, '\n')+1:]))) + 1
+	}
+	return ret
+}
+
+// ParseJS 解析 JavaScript 源码，返回语法错误，无错误时返回 nil
+func ParseJS(file string, data []byte) *JSSyntaxError {
+	if _, err := js.Parse(parse.NewInputBytes(data), js.Options{}); nil != err {
+		ret := &JSSyntaxError{File: file, Message: err.Error()}
+		var parseErr *parse.Error
+		if errors.As(err, &parseErr) {
+			ret.Line, ret.Column, ret.Message = parseErr.Line, parseErr.Column, parseErr.Message
+		}
+		return ret
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// KeywordsFileName 关键词分类表文件名（位于仓库根目录），格式为 {"规范标签": ["别名", ...]}
+const KeywordsFileName = "keywords.json"
+
+// TagsName 标签索引的文件名（不含扩展名），即 stage/tags.json
+const TagsName = "tags"
+
+// NormalizeKeyword 规范化关键词：Unicode NFC、去除首尾空白、连续空白合并为一个空格、转为小写
+func NormalizeKeyword(keyword string) string {
+	keyword = norm.NFC.String(keyword)
+	keyword = strings.Join(strings.Fields(keyword), " ")
+	return strings.ToLower(keyword)
+}
+
+// Taxonomy 关键词分类表：规范化后的关键词或别名 -> 规范标签
+type Taxonomy map[string]string
+
+// LoadTaxonomy 读取关键词分类表，规范标签与别名均按 NormalizeKeyword 规范化，别名冲突时返回错误
+func LoadTaxonomy(filePath string) (ret Taxonomy, err error) {
+	data, err := os.ReadFile(filePath)
+	if nil != err {
+		return
+	}
+	aliases := map[string][]string{}
+	if err = json.Unmarshal(data, &aliases); nil != err {
+		return
+	}
+
+	ret = Taxonomy{}
+	add := func(key, tag string) error {
+		if existing, ok := ret[key]; ok && existing != tag {
+			return fmt.Errorf("keyword [%s] maps to both [%s] and [%s]", key, existing, tag)
+		}
+		ret[key] = tag
+		return nil
+	}
+	for tag, list := range aliases {
+		tag = NormalizeKeyword(tag)
+		if err = add(tag, tag); nil != err {
+			return nil, err
+		}
+		for _, alias := range list {
+			if err = add(NormalizeKeyword(alias), tag); nil != err {
+				return nil, err
+			}
+		}
+	}
+	return
+}
+
+// KeywordChange 关键词规范化前后的值
+type KeywordChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// KeywordsResult 包关键词的规范化结果
+type KeywordsResult struct {
+	Tags       []string         `json:"tags"`       // 规范化后的标签（去重，保持原顺序）
+	Normalized []*KeywordChange `json:"normalized"` // 规范化后发生变化的关键词
+	Unknown    []string         `json:"unknown"`    // 不在分类表中的关键词（规范化后的值）
+}
+
+// Canonicalize 规范化关键词列表并映射到规范标签，不在分类表中的关键词以规范化后的值作为标签
+func (t Taxonomy) Canonicalize(keywords []string) *KeywordsResult {
+	ret := &KeywordsResult{Tags: []string{}, Normalized: []*KeywordChange{}, Unknown: []string{}}
+	seen := map[string]bool{}
+	for _, keyword := range keywords {
+		normalized := NormalizeKeyword(keyword)
+		if "" == normalized {
+			continue
+		}
+		tag, known := t[normalized]
+		if !known {
+			tag = normalized
+			ret.Unknown = append(ret.Unknown, normalized)
+		}
+		if tag != keyword {
+			ret.Normalized = append(ret.Normalized, &KeywordChange{From: keyword, To: tag})
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			ret.Tags = append(ret.Tags, tag)
+		}
+	}
+	return ret
+}
+
+// TagPackage 标签下的包
+type TagPackage struct {
+	Type string `json:"type"` // 包类型
+	Repo string `json:"repo"` // owner/repo
+}
+
+// Tag 规范标签及其包
+type Tag struct {
+	Tag      string        `json:"tag"`
+	Known    bool          `json:"known"` // 是否为分类表中的规范标签
+	Packages []*TagPackage `json:"packages"`
+}
+
+// TagsIndex 对应 stage/tags.json
+type TagsIndex struct {
+	Tags []*Tag `json:"tags"` // 按包数降序、标签名升序排列
+}
+
+// SaveTagsIndex 以两空格缩进格式写入标签索引
+func SaveTagsIndex(filePath string, index *TagsIndex) error {
+	data, err := json.MarshalIndent(index, "", "  ")
+	if nil != err {
+		return err
+	}
+	return os.WriteFile(filePath, data, 0644)
+}
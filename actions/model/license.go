@@ -0,0 +1,131 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package model
+
+import (
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// LicenseFileNames 仓库根目录下按优先级尝试的许可证文件名
+var LicenseFileNames = []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "LICENCE", "COPYING"}
+
+// licenseRule 一种 SPDX 许可证的文本指纹：规范化后的文本包含 any 中任意一组的全部短语时匹配
+type licenseRule struct {
+	id  string
+	any [][]string
+}
+
+// licenseRules 常见 SPDX 许可证的指纹，按从具体到宽泛的顺序匹配（如 AGPL、LGPL 先于 GPL）
+var licenseRules = []*licenseRule{
+	{id: "MulanPSL-2.0", any: [][]string{{"mulan permissive software license version 2"}, {"木兰宽松许可证 第2版"}}},
+	{id: "Apache-2.0", any: [][]string{{"apache license version 2 0"}}},
+	{id: "MPL-2.0", any: [][]string{{"mozilla public license version 2 0"}, {"mozilla public license v 2 0"}}},
+	{id: "EPL-2.0", any: [][]string{{"eclipse public license version 2 0"}, {"eclipse public license v 2 0"}}},
+	{id: "AGPL-3.0", any: [][]string{{"gnu affero general public license version 3"}, {"gnu affero general public license as published by the free software foundation either version 3"}}},
+	{id: "LGPL-3.0", any: [][]string{{"gnu lesser general public license version 3"}, {"gnu lesser general public license as published by the free software foundation either version 3"}}},
+	{id: "LGPL-2.1", any: [][]string{{"gnu lesser general public license version 2 1"}, {"gnu lesser general public license as published by the free software foundation either version 2 1"}}},
+	{id: "GPL-3.0", any: [][]string{{"gnu general public license version 3"}, {"gnu general public license as published by the free software foundation either version 3"}}},
+	{id: "GPL-2.0", any: [][]string{{"gnu general public license version 2"}, {"gnu general public license as published by the free software foundation either version 2"}}},
+	{id: "CC-BY-NC-SA-4.0", any: [][]string{{"attribution noncommercial sharealike 4 0 international"}}},
+	{id: "CC-BY-NC-4.0", any: [][]string{{"attribution noncommercial 4 0 international"}}},
+	{id: "CC-BY-SA-4.0", any: [][]string{{"attribution sharealike 4 0 international"}}},
+	{id: "CC-BY-4.0", any: [][]string{{"creative commons attribution 4 0 international"}}},
+	{id: "CC0-1.0", any: [][]string{{"cc0 1 0 universal"}}},
+	{id: "Unlicense", any: [][]string{{"this is free and unencumbered software released into the public domain"}}},
+	{id: "WTFPL", any: [][]string{{"do what the fuck you want to public license"}}},
+	{id: "BSD-3-Clause", any: [][]string{{"redistribution and use in source and binary forms with or without modification are permitted", "neither the name of"}}},
+	{id: "BSD-2-Clause", any: [][]string{{"redistribution and use in source and binary forms with or without modification are permitted"}}},
+	{id: "MIT", any: [][]string{{"permission is hereby granted free of charge to any person obtaining a copy", "the above copyright notice and this permission notice shall be included"}}},
+	{id: "ISC", any: [][]string{{"permission to use copy modify and or distribute this software for any purpose with or without fee is hereby granted", "provided that the above copyright notice and this permission notice appear in all copies"}}},
+	{id: "0BSD", any: [][]string{{"permission to use copy modify and or distribute this software for any purpose with or without fee is hereby granted"}}},
+	{id: "Zlib", any: [][]string{{"altered source versions must be plainly marked as such", "this notice may not be removed or altered from any source distribution"}}},
+}
+
+// spdxIdentifierRegexp 文件中显式声明的 SPDX 标识符
+var spdxIdentifierRegexp = regexp.MustCompile(`SPDX-License-Identifier:\s*([A-Za-z0-9][A-Za-z0-9.+-]*)`)
+
+// IsLicenseFile 判断包根目录或仓库根目录下的文件是否为许可证文件，如 LICENSE、LICENSE.md、COPYING、LICENSE-MIT
+func IsLicenseFile(name string) bool {
+	if strings.Contains(name, "/") {
+		return false
+	}
+	base := strings.ToUpper(strings.TrimSuffix(name, path.Ext(name)))
+	for _, prefix := range []string{"LICENSE", "LICENCE", "COPYING", "UNLICENSE"} {
+		if base == prefix || strings.HasPrefix(base, prefix+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectLicense 离线识别许可证文本，返回 SPDX 标识符，无法识别时返回空字符串
+func DetectLicense(data []byte) string {
+	if m := spdxIdentifierRegexp.FindSubmatch(data); nil != m {
+		return string(m[1])
+	}
+
+	text := normalizeLicenseText(string(data))
+	for _, rule := range licenseRules {
+		if rule.match(text) {
+			return rule.id
+		}
+	}
+	return ""
+}
+
+// match 判断规范化后的许可证文本是否符合指纹
+func (rule *licenseRule) match(text string) bool {
+	for _, phrases := range rule.any {
+		matched := true
+		for _, phrase := range phrases {
+			if !strings.Contains(text, phrase) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeLicenseText 转为小写，将标点与空白统一为单个空格，以忽略排版差异
+func normalizeLicenseText(text string) string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// DetectLicense 识别包根目录下许可证文件的许可证，返回第一个识别成功的文件及 SPDX 标识符；
+// 没有可识别的许可证文件时 id 为空，file 为找到的第一个许可证文件（可能为空）
+func (pz *PackageZip) DetectLicense() (file, id string) {
+	for _, name := range pz.Names() {
+		if !IsLicenseFile(name) {
+			continue
+		}
+		data, err := pz.ReadFile(name)
+		if nil != err {
+			continue
+		}
+		if "" == file {
+			file = name
+		}
+		if id = DetectLicense(data); "" != id {
+			return name, id
+		}
+	}
+	return
+}
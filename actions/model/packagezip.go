@@ -0,0 +1,146 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package model
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// maxPackageFileSize 从 package.zip 中读取单个文件的大小上限
+const maxPackageFileSize = 16 << 20
+
+// requiredContent 各类型 package.zip 中必须包含的入口文件，模板没有固定入口，要求至少包含一个 .md 文件
+var requiredContent = map[string]string{
+	TypePlugins: "index.js",
+	TypeThemes:  "theme.css",
+	TypeIcons:   "icon.js",
+	TypeWidgets: "index.html",
+}
+
+// PackageZip 解析后的 package.zip，路径相对于包根目录。与思源安装时的处理一致，
+// zip 中只有一个顶层目录时以该目录为包根目录
+type PackageZip struct {
+	files map[string]*zip.File
+}
+
+// OpenPackageZip 解析 package.zip
+func OpenPackageZip(data []byte) (ret *PackageZip, err error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if nil != err {
+		return
+	}
+
+	files := map[string]*zip.File{}
+	roots := map[string]bool{} // 文件路径的第一段，文件位于 zip 根目录时为空字符串
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		name := strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(f.Name, "\\", "/")), "/")
+		if "" == name {
+			continue
+		}
+		files[name] = f
+		root, _, found := strings.Cut(name, "/")
+		if !found {
+			root = ""
+		}
+		roots[root] = true
+	}
+
+	if 1 == len(roots) && !roots[""] {
+		for root := range roots {
+			rooted := map[string]*zip.File{}
+			for name, f := range files {
+				rooted[strings.TrimPrefix(name, root+"/")] = f
+			}
+			files = rooted
+		}
+	}
+	ret = &PackageZip{files: files}
+	return
+}
+
+// Names 返回所有文件路径，按字典序排列
+func (pz *PackageZip) Names() (ret []string) {
+	for name := range pz.files {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return
+}
+
+// Has 判断文件是否存在
+func (pz *PackageZip) Has(name string) bool {
+	_, ok := pz.files[name]
+	return ok
+}
+
+// ReadFile 读取文件内容，文件超过大小上限时返回错误
+func (pz *PackageZip) ReadFile(name string) ([]byte, error) {
+	f, ok := pz.files[name]
+	if !ok {
+		return nil, fmt.Errorf("file [%s] not found", name)
+	}
+	if maxPackageFileSize < f.UncompressedSize64 {
+		return nil, fmt.Errorf("file [%s] is larger than %d MB", name, maxPackageFileSize>>20)
+	}
+	r, err := f.Open()
+	if nil != err {
+		return nil, err
+	}
+	defer r.Close()
+	data, err := io.ReadAll(io.LimitReader(r, maxPackageFileSize+1))
+	if nil != err {
+		return nil, err
+	}
+	if maxPackageFileSize < len(data) {
+		return nil, fmt.Errorf("file [%s] is larger than %d MB", name, maxPackageFileSize>>20)
+	}
+	return data, nil
+}
+
+// ContentRule 一条按类型必须包含的内容规则
+type ContentRule struct {
+	Name  string   `json:"name"`  // 规则描述，如 index.js、*.md
+	Pass  bool     `json:"pass"`  // 是否满足
+	Files []string `json:"files"` // 满足规则的文件
+}
+
+// CheckRequiredContent 按类型检查 package.zip 中必须包含的内容
+func (pz *PackageZip) CheckRequiredContent(typ string) (ret []*ContentRule) {
+	if entry, ok := requiredContent[typ]; ok {
+		rule := &ContentRule{Name: entry, Pass: pz.Has(entry)}
+		if rule.Pass {
+			rule.Files = []string{entry}
+		}
+		return []*ContentRule{rule}
+	}
+
+	if TypeTemplates == typ {
+		rule := &ContentRule{Name: "*.md"}
+		for _, name := range pz.Names() {
+			// README 不是模板
+			if strings.HasSuffix(strings.ToLower(name), ".md") && !strings.HasPrefix(strings.ToLower(path.Base(name)), "readme") {
+				rule.Files = append(rule.Files, name)
+			}
+		}
+		rule.Pass = 0 < len(rule.Files)
+		ret = append(ret, rule)
+	}
+	return
+}
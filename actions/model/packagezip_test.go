@@ -0,0 +1,110 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package model
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// newTestZip 在内存中打包 files（路径 -> 内容），路径以 / 结尾时写入目录条目
+func newTestZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	w := zip.NewWriter(buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if nil != err {
+			t.Fatal(err)
+		}
+		if _, err = f.Write([]byte(content)); nil != err {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); nil != err {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestOpenPackageZip(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []string
+	}{
+		{name: "flat", files: map[string]string{"index.js": "", "plugin.json": ""}, want: []string{"index.js", "plugin.json"}},
+		{name: "single top-level directory", files: map[string]string{"dist/": "", "dist/index.js": "", "dist/i18n/en_US.json": ""}, want: []string{"i18n/en_US.json", "index.js"}},
+		{name: "multiple top-level directories", files: map[string]string{"a/index.js": "", "b/plugin.json": ""}, want: []string{"a/index.js", "b/plugin.json"}},
+		{name: "top-level file and directory", files: map[string]string{"index.js": "", "i18n/en_US.json": ""}, want: []string{"i18n/en_US.json", "index.js"}},
+		{name: "backslash separators", files: map[string]string{"dist\\index.js": "", "dist\\plugin.json": ""}, want: []string{"index.js", "plugin.json"}},
+		{name: "path traversal", files: map[string]string{"../../index.js": ""}, want: []string{"index.js"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pz, err := OpenPackageZip(newTestZip(t, tt.files))
+			if nil != err {
+				t.Fatal(err)
+			}
+			if got := pz.Names(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Names() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckRequiredContent(t *testing.T) {
+	tests := []struct {
+		name      string
+		typ       string
+		files     map[string]string
+		wantName  string
+		wantPass  bool
+		wantFiles []string
+	}{
+		{name: "plugin", typ: TypePlugins, files: map[string]string{"index.js": "", "plugin.json": ""}, wantName: "index.js", wantPass: true, wantFiles: []string{"index.js"}},
+		{name: "plugin in directory", typ: TypePlugins, files: map[string]string{"dist/index.js": ""}, wantName: "index.js", wantPass: true, wantFiles: []string{"index.js"}},
+		{name: "plugin without index.js", typ: TypePlugins, files: map[string]string{"main.js": ""}, wantName: "index.js"},
+		{name: "nested index.js", typ: TypePlugins, files: map[string]string{"index.js/": "", "src/index.js": "", "plugin.json": ""}, wantName: "index.js"},
+		{name: "theme", typ: TypeThemes, files: map[string]string{"theme.css": ""}, wantName: "theme.css", wantPass: true, wantFiles: []string{"theme.css"}},
+		{name: "theme without theme.css", typ: TypeThemes, files: map[string]string{"style.css": ""}, wantName: "theme.css"},
+		{name: "icon", typ: TypeIcons, files: map[string]string{"icon.js": ""}, wantName: "icon.js", wantPass: true, wantFiles: []string{"icon.js"}},
+		{name: "widget", typ: TypeWidgets, files: map[string]string{"index.html": ""}, wantName: "index.html", wantPass: true, wantFiles: []string{"index.html"}},
+		{name: "widget without index.html", typ: TypeWidgets, files: map[string]string{"widget.json": ""}, wantName: "index.html"},
+		{name: "template", typ: TypeTemplates, files: map[string]string{"daily.md": "", "sub/weekly.MD": "", "README.md": ""}, wantName: "*.md", wantPass: true, wantFiles: []string{"daily.md", "sub/weekly.MD"}},
+		{name: "template with only READMEs", typ: TypeTemplates, files: map[string]string{"README.md": "", "README_zh_CN.md": "", "docs/readme.md": ""}, wantName: "*.md"},
+		{name: "template without markdown", typ: TypeTemplates, files: map[string]string{"template.json": ""}, wantName: "*.md"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pz, err := OpenPackageZip(newTestZip(t, tt.files))
+			if nil != err {
+				t.Fatal(err)
+			}
+			rules := pz.CheckRequiredContent(tt.typ)
+			if 1 != len(rules) {
+				t.Fatalf("CheckRequiredContent(%q) returned %d rules, want 1", tt.typ, len(rules))
+			}
+			rule := rules[0]
+			if rule.Name != tt.wantName || rule.Pass != tt.wantPass || !reflect.DeepEqual(rule.Files, tt.wantFiles) {
+				t.Errorf("CheckRequiredContent(%q) = %+v, want {Name:%s Pass:%v Files:%v}", tt.typ, rule, tt.wantName, tt.wantPass, tt.wantFiles)
+			}
+		})
+	}
+}
+
+func TestOpenPackageZipInvalid(t *testing.T) {
+	if _, err := OpenPackageZip([]byte("not a zip")); nil == err {
+		t.Error("OpenPackageZip() error = nil, want an error")
+	}
+}
@@ -0,0 +1,89 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package model
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// ReadmeFileName 包根目录下的默认 README 文件，是 README 解析的最终回退
+const ReadmeFileName = "README.md"
+
+// legacyReadmes 清单未配置 readme 字段时按约定存在的多语言 README
+var legacyReadmes = LocaleStrings{"zh_CN": "README_zh_CN.md", "en_US": "README_en_US.md"}
+
+// NormalizeReadmePath 规范化并校验 readme 路径，防止路径穿越；返回规范化后的相对路径（无前导斜杠）及是否合法
+func NormalizeReadmePath(readmePath string) (string, bool) {
+	readmePath = strings.TrimSpace(readmePath)
+	if readmePath == "" {
+		return "", false
+	}
+	// 去掉前导斜杠/反斜杠，视为相对路径
+	readmePath = strings.TrimLeft(readmePath, "/\\")
+	// 统一为正向斜杠后交给 filepath 做跨平台清理
+	cleaned := filepath.Clean(filepath.FromSlash(readmePath))
+	normalized := filepath.ToSlash(cleaned)
+	// 拒绝含 .. 的路径，防止路径穿越
+	if strings.Contains(normalized, "..") {
+		return "", false
+	}
+	return normalized, true
+}
+
+// DeclaredReadmes 返回清单声明的各 locale README 路径（已规范化，非法路径被忽略）；
+// 未配置 readme 字段时返回约定的 README_zh_CN.md、README_en_US.md，legacy 为 true
+func (pkg *Package) DeclaredReadmes() (ret LocaleStrings, legacy bool) {
+	ret = LocaleStrings{}
+	for locale, readmePath := range pkg.Readme {
+		if normalized, ok := NormalizeReadmePath(readmePath); ok {
+			ret[locale] = normalized
+		}
+	}
+	if 0 == len(ret) {
+		for locale, readmePath := range legacyReadmes {
+			ret[locale] = readmePath
+		}
+		legacy = true
+	}
+	return
+}
+
+// ResolveReadme 按 locale -> default -> README.md 的顺序解析 default 及 locales 中每个 locale 实际使用的 README 路径，
+// exists 判断路径在发布提交中是否存在；missing 为清单中声明了但不存在的路径（locale -> 路径），约定的 README 不存在时不计入
+func (pkg *Package) ResolveReadme(locales []string, exists func(readmePath string) bool) (resolved, missing LocaleStrings) {
+	resolved, missing = LocaleStrings{}, LocaleStrings{}
+	declared, legacy := pkg.DeclaredReadmes()
+	available := LocaleStrings{}
+	for locale, readmePath := range declared {
+		if exists(readmePath) {
+			available[locale] = readmePath
+		} else if !legacy {
+			missing[locale] = readmePath
+		}
+	}
+
+	fallback := available["default"]
+	if "" == fallback && exists(ReadmeFileName) {
+		fallback = ReadmeFileName
+	}
+	if "" != fallback {
+		resolved["default"] = fallback
+	}
+	for _, locale := range locales {
+		if readmePath := available[locale]; "" != readmePath {
+			resolved[locale] = readmePath
+		} else if "" != fallback {
+			resolved[locale] = fallback
+		}
+	}
+	return
+}
@@ -0,0 +1,118 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package model
+
+import (
+	"encoding/json"
+	"os"
+	"unicode"
+)
+
+// SearchDir 搜索索引所在目录（相对 stage），每个 locale 一个文件，如 stage/search/zh_CN.json
+const SearchDir = "search"
+
+// SearchSchemaVersion 搜索索引的格式版本，格式或分词规则不兼容变更时递增
+const SearchSchemaVersion = 1
+
+// Locales 思源支持的界面语言，为每个 locale 生成一份搜索索引
+var Locales = []string{
+	"ar_SA", "de_DE", "en_US", "es_ES", "fr_FR", "he_IL", "it_IT", "ja_JP",
+	"ko_KR", "pl_PL", "pt_BR", "ru_RU", "sk_SK", "tr_TR", "zh_CHT", "zh_CN",
+}
+
+// 搜索字段及其权重，同一词项在多个字段中出现时权重累加
+const (
+	SearchFieldName        = "name"
+	SearchFieldDisplayName = "displayName"
+	SearchFieldKeywords    = "keywords"
+	SearchFieldDescription = "description"
+)
+
+// SearchFieldWeights 各搜索字段的权重
+var SearchFieldWeights = map[string]int{
+	SearchFieldName:        8,
+	SearchFieldDisplayName: 10,
+	SearchFieldKeywords:    6,
+	SearchFieldDescription: 2,
+}
+
+// SearchPackage 搜索索引中的包，在 Packages 中的下标即包 id
+type SearchPackage struct {
+	Type string `json:"type"` // 包类型
+	Repo string `json:"repo"` // owner/repo
+	Name string `json:"name"` // package.name
+}
+
+// SearchPosting 倒排项：[包 id, 权重]
+type SearchPosting [2]int
+
+// SearchIndex 对应 stage/search/<locale>.json
+type SearchIndex struct {
+	SchemaVersion int                        `json:"schemaVersion"`
+	Locale        string                     `json:"locale"`
+	Fields        map[string]int             `json:"fields"`   // 字段权重
+	Packages      []*SearchPackage           `json:"packages"` // 包列表，按类型、owner/repo 排序以保持 id 稳定
+	Terms         map[string][]SearchPosting `json:"terms"`    // 词项 -> 倒排列表，按权重降序
+}
+
+// SaveSearchIndex 以紧凑格式写入搜索索引
+func SaveSearchIndex(filePath string, index *SearchIndex) error {
+	data, err := json.Marshal(index)
+	if nil != err {
+		return err
+	}
+	return os.WriteFile(filePath, data, 0644)
+}
+
+// Tokenize 将文本切分为搜索词项，客户端对查询串分词时须使用相同规则：
+//   - 字母数字按非字母数字字符切分并转为小写，忽略单个字符的词
+//   - 连续的中日韩字符切分为二元组（bigram），仅有一个字符时保留该字符
+func Tokenize(text string) (ret []string) {
+	var word, cjk []rune
+	flushWord := func() {
+		if 1 < len(word) {
+			ret = append(ret, string(word))
+		}
+		word = word[:0]
+	}
+	flushCJK := func() {
+		if 1 == len(cjk) {
+			ret = append(ret, string(cjk))
+		}
+		for i := 0; i+1 < len(cjk); i++ {
+			ret = append(ret, string(cjk[i:i+2]))
+		}
+		cjk = cjk[:0]
+	}
+
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word = append(word, unicode.ToLower(r))
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+	return
+}
+
+// isCJK 判断是否为中日韩表意文字、假名或谚文
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r)
+}
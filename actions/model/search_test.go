@@ -0,0 +1,41 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "empty", text: ""},
+		{name: "latin", text: "Hello, World-2024!", want: []string{"hello", "world", "2024"}},
+		{name: "single letters dropped", text: "a B cd", want: []string{"cd"}},
+		{name: "non-ascii letters", text: "Ärger Café", want: []string{"ärger", "café"}},
+		{name: "cjk bigram", text: "思源笔记", want: []string{"思源", "源笔", "笔记"}},
+		{name: "single cjk", text: "书", want: []string{"书"}},
+		{name: "mixed", text: "SiYuan思源插件v2", want: []string{"siyuan", "思源", "源插", "插件", "v2"}},
+		{name: "cjk separated by punctuation", text: "主题，图标", want: []string{"主题", "图标"}},
+		{name: "kana and hangul", text: "テスト 테마", want: []string{"テス", "スト", "테마"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Tokenize(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package model
+
+import (
+	"bytes"
+	"math"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// 凭据泄露扫描的文件来源
+const (
+	SecretSourcePackageZip = "package.zip"
+	SecretSourceRepository = "repository"
+)
+
+// 凭据泄露扫描的限制
+const (
+	maxSecretScanFiles       = 512     // 单个来源扫描的文件数上限
+	MaxSecretScanFileSize    = 1 << 20 // 扫描内容的单个文件大小上限
+	maxSecretFindingsPerFile = 8       // 单个文件报告的发现数上限
+	minEntropySecretLen      = 32      // 高熵字符串的最小长度
+	maxEntropySecretLen      = 128     // 高熵字符串的最大长度，更长的通常是编码数据
+	minSecretEntropy         = 4.5     // 高熵字符串的香农熵下限（比特/字符）
+	secretKindForbiddenFile  = "forbidden file"
+)
+
+// secretPattern 已知格式的凭据
+type secretPattern struct {
+	kind    string
+	pattern *regexp.Regexp
+	group   int // 凭据所在的捕获组，0 为整个匹配
+}
+
+// secretPatterns 已知格式的凭据规则
+var secretPatterns = []*secretPattern{
+	{kind: "GitHub token", pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,255}|github_pat_[A-Za-z0-9_]{60,255})\b`)},
+	{kind: "OpenAI API key", pattern: regexp.MustCompile(`\bsk-(?:proj-|svcacct-|admin-)?[A-Za-z0-9_-]{20,}T3BlbkFJ[A-Za-z0-9_-]{20,}\b|\bsk-(?:proj|svcacct|admin)-[A-Za-z0-9_-]{40,}\b`)},
+	{kind: "AWS access key ID", pattern: regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA|ANPA|ANVA|AIPA)[0-9A-Z]{16}\b`)},
+	{kind: "AWS secret access key", pattern: regexp.MustCompile(`(?i)aws_?secret_?(?:access_?)?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})\b`), group: 1},
+	{kind: "private key", pattern: regexp.MustCompile(`-----BEGIN (?:[A-Z0-9]+ )*PRIVATE KEY(?: BLOCK)?-----`)},
+}
+
+// secretLockFiles 依赖锁文件，其中大量的完整性哈希会被误报为高熵字符串，不扫描内容
+var secretLockFiles = map[string]bool{
+	"package-lock.json": true, "npm-shrinkwrap.json": true, "pnpm-lock.yaml": true, "yarn.lock": true, "bun.lock": true,
+}
+
+// secretHashPrefixes 子资源完整性等哈希值的前缀，带这些前缀的字符串不是凭据
+var secretHashPrefixes = []string{"sha1-", "sha256-", "sha384-", "sha512-"}
+
+var (
+	// credentialLiteralRegexp 赋给凭据类名称（如 apiKey、token、secret、password）的引号内字符串，用于高熵检测
+	credentialLiteralRegexp = regexp.MustCompile(`(?i)(?:key|token|secret|passw(?:or)?d|credential|auth)[\w-]*["'` + "`" + `]?\s*[:=]\s*["'` + "`" + `]([A-Za-z0-9+/_=.-]{32,128})["'` + "`" + `]`)
+	// npmrcTokenRegexp .npmrc 中的认证配置
+	npmrcTokenRegexp = regexp.MustCompile(`(?m)(?:_authToken|_auth|_password)\s*=\s*\S+`)
+)
+
+// SecretFinding 凭据泄露扫描的一条发现，Redacted 中仅保留凭据的前几个字符
+type SecretFinding struct {
+	Source   string `json:"source"`   // package.zip 或 repository
+	File     string `json:"file"`     // 文件路径
+	Line     int    `json:"line"`     // 行号，从 1 开始，整个文件违规时为 0
+	Kind     string `json:"kind"`     // 凭据类型，如 GitHub token、forbidden file
+	Redacted string `json:"redacted"` // 打码后的凭据
+}
+
+// ForbiddenSecretFile 判断文件是否为不允许发布的凭据文件，返回原因；data 为 nil 时仅按文件名判断，
+// .npmrc 只有包含认证配置时才不允许，此时需要传入内容
+func ForbiddenSecretFile(name string, data []byte) string {
+	base := strings.ToLower(path.Base(name))
+	switch {
+	case ".env" == base:
+		return ".env file"
+	case strings.HasPrefix(base, ".env.") && !isSecretTemplate(base):
+		return ".env file"
+	case "id_rsa" == base || "id_dsa" == base || "id_ecdsa" == base || "id_ed25519" == base:
+		return "SSH private key"
+	case ".npmrc" == base && nil != data && npmrcTokenRegexp.Match(data):
+		return ".npmrc with auth token"
+	}
+	return ""
+}
+
+// isSecretTemplate 判断 .env.example 等不含真实凭据的模板文件
+func isSecretTemplate(base string) bool {
+	for _, suffix := range []string{".example", ".sample", ".template", ".dist", ".defaults"} {
+		if strings.HasSuffix(base, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScanFileSecrets 扫描单个文件中的凭据，包括不允许发布的凭据文件、已知格式的凭据及赋给凭据类名称的高熵字符串；
+// 二进制文件与依赖锁文件只按文件名判断
+func ScanFileSecrets(source, file string, data []byte) (ret []*SecretFinding) {
+	if reason := ForbiddenSecretFile(file, data); "" != reason {
+		ret = append(ret, &SecretFinding{Source: source, File: file, Kind: secretKindForbiddenFile + ": " + reason})
+	}
+	if MaxSecretScanFileSize < len(data) || 0 <= bytes.IndexByte(data, 0) || secretLockFiles[strings.ToLower(path.Base(file))] {
+		return
+	}
+
+	seen := map[string]bool{}
+	add := func(kind, secret string, offset int) bool {
+		if seen[secret] {
+			return true
+		}
+		seen[secret] = true
+		redacted := RedactSecret(secret)
+		if "private key" == kind {
+			redacted = secret // 只引用 PEM 头，不含密钥内容
+		}
+		ret = append(ret, &SecretFinding{
+			Source:   source,
+			File:     file,
+			Line:     bytes.Count(data[:offset], []byte("\n")) + 1,
+			Kind:     kind,
+			Redacted: redacted,
+		})
+		return maxSecretFindingsPerFile > len(ret)
+	}
+
+	for _, p := range secretPatterns {
+		for _, m := range p.pattern.FindAllSubmatchIndex(data, -1) {
+			start, end := m[2*p.group], m[2*p.group+1]
+			if !add(p.kind, string(data[start:end]), start) {
+				return
+			}
+		}
+	}
+	for _, m := range credentialLiteralRegexp.FindAllSubmatchIndex(data, -1) {
+		literal := string(data[m[2]:m[3]])
+		if seen[literal] || !isHighEntropySecret(literal) {
+			continue
+		}
+		if !add("high-entropy string", literal, m[2]) {
+			return
+		}
+	}
+	return
+}
+
+// isHighEntropySecret 判断字符串是否像随机生成的凭据：同时含大小写字母和数字、不是十六进制或带 sha512- 等前缀的哈希、熵足够高
+func isHighEntropySecret(s string) bool {
+	if minEntropySecretLen > len(s) || maxEntropySecretLen < len(s) {
+		return false
+	}
+	for _, prefix := range secretHashPrefixes {
+		if strings.HasPrefix(s, prefix) {
+			return false
+		}
+	}
+	var upper, lower, digit bool
+	hex := true
+	for _, r := range s {
+		switch {
+		case 'A' <= r && 'Z' >= r:
+			upper = true
+		case 'a' <= r && 'z' >= r:
+			lower = true
+		case '0' <= r && '9' >= r:
+			digit = true
+		}
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			hex = false
+		}
+	}
+	if hex || !upper || !lower || !digit {
+		return false // 十六进制哈希或字符类单一的字符串
+	}
+	return minSecretEntropy <= shannonEntropy(s)
+}
+
+// shannonEntropy 计算字符串的香农熵（比特/字符）
+func shannonEntropy(s string) (ret float64) {
+	counts := map[rune]int{}
+	for _, r := range s {
+		counts[r]++
+	}
+	n := float64(len(s))
+	for _, c := range counts {
+		p := float64(c) / n
+		ret -= p * math.Log2(p)
+	}
+	return
+}
+
+// ScanSecrets 扫描 package.zip 中所有文件的凭据
+func (pz *PackageZip) ScanSecrets() (ret []*SecretFinding) {
+	for i, name := range pz.Names() {
+		if maxSecretScanFiles <= i {
+			break
+		}
+		f := pz.files[name]
+		if MaxSecretScanFileSize < f.UncompressedSize64 {
+			ret = append(ret, ScanFileSecrets(SecretSourcePackageZip, name, nil)...)
+			continue
+		}
+		data, err := pz.ReadFile(name)
+		if nil != err {
+			continue
+		}
+		ret = append(ret, ScanFileSecrets(SecretSourcePackageZip, name, data)...)
+	}
+	return
+}
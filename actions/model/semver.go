@@ -0,0 +1,115 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// semverRegexp 语义化版本，允许前缀 v、省略 patch，允许预发布与构建元数据
+var semverRegexp = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)(?:\.(0|[1-9]\d*))?(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?(?:\+[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?$`)
+
+// Version 语义化版本
+type Version struct {
+	Major, Minor, Patch int
+	Prerelease          []string // 预发布标识，如 beta.1 -> [beta 1]
+}
+
+// ParseVersion 解析语义化版本，如 2.10.14、v1.0.0-beta.1、1.2
+func ParseVersion(s string) (ret Version, ok bool) {
+	m := semverRegexp.FindStringSubmatch(strings.TrimSpace(s))
+	if nil == m {
+		return
+	}
+	ret.Major, _ = strconv.Atoi(m[1])
+	ret.Minor, _ = strconv.Atoi(m[2])
+	if "" != m[3] {
+		ret.Patch, _ = strconv.Atoi(m[3])
+	}
+	if "" != m[4] {
+		ret.Prerelease = strings.Split(m[4], ".")
+	}
+	ok = true
+	return
+}
+
+func (v Version) String() string {
+	ret := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if 0 < len(v.Prerelease) {
+		ret += "-" + strings.Join(v.Prerelease, ".")
+	}
+	return ret
+}
+
+// MinorString 返回 major.minor，如 2.10
+func (v Version) MinorString() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// Compare 按语义化版本优先级比较，返回 -1、0、1
+func (v Version) Compare(o Version) int {
+	for _, d := range [][2]int{{v.Major, o.Major}, {v.Minor, o.Minor}, {v.Patch, o.Patch}} {
+		if d[0] != d[1] {
+			if d[0] < d[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	// 有预发布标识的版本优先级较低
+	if 0 == len(v.Prerelease) || 0 == len(o.Prerelease) {
+		switch {
+		case len(v.Prerelease) == len(o.Prerelease):
+			return 0
+		case 0 == len(v.Prerelease):
+			return 1
+		default:
+			return -1
+		}
+	}
+	for i := 0; i < len(v.Prerelease) && i < len(o.Prerelease); i++ {
+		if c := comparePrereleaseIdentifier(v.Prerelease[i], o.Prerelease[i]); 0 != c {
+			return c
+		}
+	}
+	switch {
+	case len(v.Prerelease) < len(o.Prerelease):
+		return -1
+	case len(v.Prerelease) > len(o.Prerelease):
+		return 1
+	}
+	return 0
+}
+
+// comparePrereleaseIdentifier 数字标识按数值比较且低于非数字标识，非数字标识按 ASCII 比较
+func comparePrereleaseIdentifier(a, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	switch {
+	case nil == errA && nil == errB:
+		switch {
+		case na < nb:
+			return -1
+		case na > nb:
+			return 1
+		}
+		return 0
+	case nil == errA:
+		return -1
+	case nil == errB:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,253 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package model 定义 stage/*.json 的类型化数据模型，供 stage、check、index 共用。
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// 集市包类型，与根目录下的 *.txt 列表文件及 stage/*.json 文件名一致
+const (
+	TypeThemes    = "themes"
+	TypeTemplates = "templates"
+	TypeIcons     = "icons"
+	TypeWidgets   = "widgets"
+	TypePlugins   = "plugins"
+)
+
+// Types 所有集市包类型，按 stage 处理顺序排列
+var Types = []string{TypeThemes, TypeTemplates, TypeIcons, TypeWidgets, TypePlugins}
+
+// ManifestName 返回类型对应的清单文件名（不含扩展名），如 plugins -> plugin
+func ManifestName(typ string) string {
+	return strings.TrimSuffix(typ, "s")
+}
+
+// LocaleStrings 表示按 locale 键（如 default、zh_CN、en_US）组织的多语言字符串
+type LocaleStrings map[string]string
+
+// LocalizedString 按 locale -> default 的顺序取多语言字符串，均为空时返回空字符串
+func (ls LocaleStrings) LocalizedString(locale string) string {
+	if v := strings.TrimSpace(ls[locale]); "" != v {
+		return v
+	}
+	return strings.TrimSpace(ls["default"])
+}
+
+type Funding struct {
+	OpenCollective string   `json:"openCollective"`
+	Patreon        string   `json:"patreon"`
+	GitHub         string   `json:"github"`
+	Custom         []string `json:"custom"`
+}
+
+// Links 返回赞助链接，openCollective、patreon、github 字段为用户名时补全为完整链接
+func (f *Funding) Links() (ret []string) {
+	if nil == f {
+		return
+	}
+	add := func(value, prefix string) {
+		value = strings.TrimSpace(value)
+		if "" == value {
+			return
+		}
+		if !strings.HasPrefix(value, "http://") && !strings.HasPrefix(value, "https://") {
+			value = prefix + value
+		}
+		ret = append(ret, value)
+	}
+	add(f.OpenCollective, "https://opencollective.com/")
+	add(f.Patreon, "https://www.patreon.com/")
+	add(f.GitHub, "https://github.com/sponsors/")
+	for _, custom := range f.Custom {
+		if custom = strings.TrimSpace(custom); "" != custom {
+			ret = append(ret, custom)
+		}
+	}
+	return
+}
+
+type Package struct {
+	Name          string        `json:"name"`
+	Author        string        `json:"author"`
+	URL           string        `json:"url"`
+	Version       string        `json:"version"`
+	MinAppVersion string        `json:"minAppVersion"`
+	MaxAppVersion string        `json:"maxAppVersion,omitempty"` // 可选，最高兼容的思源版本
+	DisplayName   LocaleStrings `json:"displayName"`
+	Description   LocaleStrings `json:"description"`
+	Readme        LocaleStrings `json:"readme"`
+	Funding       *Funding      `json:"funding"`
+	Keywords      []string      `json:"keywords"`
+}
+
+// Base 返回包的公共配置
+func (pkg *Package) Base() *Package {
+	return pkg
+}
+
+// PluginPackage 插件的 package
+type PluginPackage struct {
+	*Package
+	Backends          []string `json:"backends"`
+	Frontends         []string `json:"frontends"`
+	DisabledInPublish bool     `json:"disabledInPublish"`
+}
+
+// ThemePackage 主题的 package
+type ThemePackage struct {
+	*Package
+	Modes []string `json:"modes"`
+}
+
+// Manifest 为 *Package（模板/图标/挂件）、*PluginPackage（插件）或 *ThemePackage（主题），通过 Base 访问公共配置
+type Manifest interface {
+	Base() *Package
+}
+
+// NewManifest 按类型创建空的包配置
+func NewManifest(typ string) Manifest {
+	switch typ {
+	case TypePlugins:
+		return &PluginPackage{Package: &Package{}}
+	case TypeThemes:
+		return &ThemePackage{Package: &Package{}}
+	default:
+		return &Package{}
+	}
+}
+
+// ParseManifest 按类型解析清单文件内容
+func ParseManifest(data []byte, typ string) (Manifest, error) {
+	ret := NewManifest(typ)
+	if err := json.Unmarshal(data, ret); nil != err {
+		return nil, err
+	}
+	return ret, nil
+}
+
+type StageRepo struct {
+	URL         string `json:"url"`
+	Updated     string `json:"updated"`
+	Stars       int    `json:"stars"`
+	OpenIssues  int    `json:"openIssues"`
+	Size        int64  `json:"size"`
+	InstallSize int64  `json:"installSize"`
+
+	Trending *Trending     `json:"trending,omitempty"` // 热度，根据 stage/stats.jsonl 计算
+	Images   *Images       `json:"images,omitempty"`   // 预览图、图标的缩略图及 WebP 变体
+	Readme   LocaleStrings `json:"readme,omitempty"`   // default 及各 locale 实际使用的 README 路径，已按 locale -> default -> README.md 回退
+	License  string        `json:"license,omitempty"`  // 仓库或 package.zip 中许可证文件的 SPDX 标识符，无法识别时为空
+	Archived bool          `json:"archived,omitempty"` // 仓库已归档，不再维护
+
+	Package Manifest `json:"package"`
+}
+
+// RepoPath 返回 owner/repo（去掉 URL 中的 @hash 部分），URL 不合法时返回空字符串
+func (repo *StageRepo) RepoPath() string {
+	idx := strings.Index(repo.URL, "@")
+	if idx <= 0 {
+		return ""
+	}
+	return repo.URL[:idx]
+}
+
+// Hash 返回 URL 中 @ 之后的 release 提交 hash
+func (repo *StageRepo) Hash() string {
+	idx := strings.Index(repo.URL, "@")
+	if idx < 0 {
+		return ""
+	}
+	return repo.URL[idx+1:]
+}
+
+// StageFile 对应 stage/<type>.json
+type StageFile struct {
+	Repos []*StageRepo `json:"repos"`
+}
+
+// rawStageRepo 用于解析：package 字段的具体类型取决于包类型，先保留原始 JSON
+type rawStageRepo struct {
+	StageRepo
+	Package json.RawMessage `json:"package"`
+}
+
+// ParseStageFile 按类型解析 stage 文件内容，package 字段解析为对应类型的 Manifest
+func ParseStageFile(data []byte, typ string) (ret *StageFile, err error) {
+	raw := struct {
+		Repos []*rawStageRepo `json:"repos"`
+	}{}
+	if err = json.Unmarshal(data, &raw); nil != err {
+		return
+	}
+
+	ret = &StageFile{Repos: make([]*StageRepo, 0, len(raw.Repos))}
+	for i, r := range raw.Repos {
+		if nil == r {
+			continue
+		}
+		repo := r.StageRepo
+		if 0 < len(r.Package) && "null" != string(r.Package) {
+			if repo.Package, err = ParseManifest(r.Package, typ); nil != err {
+				return nil, fmt.Errorf("repo #%d [%s]: %w", i, repo.URL, err)
+			}
+		}
+		ret.Repos = append(ret.Repos, &repo)
+	}
+	return
+}
+
+// LoadStageFile 读取并解析 stage 文件
+func LoadStageFile(filePath, typ string) (*StageFile, error) {
+	data, err := os.ReadFile(filePath)
+	if nil != err {
+		return nil, err
+	}
+	return ParseStageFile(data, typ)
+}
+
+// SaveStageFile 以两空格缩进格式写入 stage 文件
+func SaveStageFile(filePath string, stageFile *StageFile) error {
+	data, err := json.MarshalIndent(stageFile, "", "  ")
+	if nil != err {
+		return err
+	}
+	return os.WriteFile(filePath, data, 0644)
+}
+
+// RepoMap 返回以 owner/repo 为 key 的映射，URL 不合法的条目被忽略
+func (f *StageFile) RepoMap() map[string]*StageRepo {
+	ret := make(map[string]*StageRepo, len(f.Repos))
+	for _, repo := range f.Repos {
+		if key := repo.RepoPath(); "" != key {
+			ret[key] = repo
+		}
+	}
+	return ret
+}
+
+// Names 返回所有包的 package.name
+func (f *StageFile) Names() []string {
+	ret := make([]string, 0, len(f.Repos))
+	for _, repo := range f.Repos {
+		if nil == repo.Package {
+			continue
+		}
+		if name := repo.Package.Base().Name; "" != name {
+			ret = append(ret, name)
+		}
+	}
+	return ret
+}
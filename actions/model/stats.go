@@ -0,0 +1,136 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package model
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+// StatsFileName 统计时间序列文件名（相对 stage），JSON Lines 格式，每天追加一行
+const StatsFileName = "stats.jsonl"
+
+// StatsDateLayout 统计日期格式（UTC）
+const StatsDateLayout = "2006-01-02"
+
+// StatsPoint 单个包某日的统计：[stars, downloads, size]，downloads 为各发布版本 package.zip 下载次数之和
+type StatsPoint [3]int64
+
+func (p StatsPoint) Stars() int64     { return p[0] }
+func (p StatsPoint) Downloads() int64 { return p[1] }
+func (p StatsPoint) Size() int64      { return p[2] }
+
+// StatsDay 某日所有包的统计，对应 stats.jsonl 中的一行
+type StatsDay struct {
+	Date  string                `json:"date"`
+	Repos map[string]StatsPoint `json:"repos"` // owner/repo -> 统计
+}
+
+// StatsHistory 按日期升序排列的统计时间序列
+type StatsHistory struct {
+	Days []*StatsDay
+}
+
+// LoadStatsHistory 读取统计时间序列，文件不存在时返回空序列
+func LoadStatsHistory(filePath string) (ret *StatsHistory, err error) {
+	ret = &StatsHistory{}
+	data, err := os.ReadFile(filePath)
+	if nil != err {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if 0 == len(line) {
+			continue
+		}
+		day := &StatsDay{}
+		if err = json.Unmarshal(line, day); nil != err {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		ret.Days = append(ret.Days, day)
+	}
+	err = scanner.Err()
+	return
+}
+
+// HasDate 判断是否已记录指定日期的统计
+func (h *StatsHistory) HasDate(date string) bool {
+	return 0 < len(h.Days) && h.Days[len(h.Days)-1].Date >= date
+}
+
+// AppendStatsDay 向统计文件追加一行，已有记录不会被修改
+func AppendStatsDay(filePath string, day *StatsDay) error {
+	data, err := json.Marshal(day)
+	if nil != err {
+		return err
+	}
+	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if nil != err {
+		return err
+	}
+	if _, err = f.Write(append(data, '\n')); nil != err {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Trending 基于统计时间序列计算的热度
+type Trending struct {
+	Stars7d  int64   `json:"stars7d"`  // 近 7 天 star 增长
+	Stars30d int64   `json:"stars30d"` // 近 30 天 star 增长
+	Score    float64 `json:"score"`    // 热度分：近 7 天与近 30 天日均 star 增长的加权和
+}
+
+// Trending 计算包在 now 时的热度，stars 为当前 star 数；没有历史记录时返回 nil
+func (h *StatsHistory) Trending(repo string, stars int64, now time.Time) *Trending {
+	base7, ok7 := h.baseline(repo, now.AddDate(0, 0, -7))
+	base30, ok30 := h.baseline(repo, now.AddDate(0, 0, -30))
+	if !ok7 || !ok30 {
+		return nil
+	}
+
+	ret := &Trending{Stars7d: stars - base7.Stars(), Stars30d: stars - base30.Stars()}
+	score := 0.7*float64(ret.Stars7d)/7 + 0.3*float64(ret.Stars30d)/30
+	ret.Score = math.Round(score*100) / 100
+	return ret
+}
+
+// baseline 返回 since 当日或之前最近的统计，窗口内没有更早的记录时退化为最早的记录
+func (h *StatsHistory) baseline(repo string, since time.Time) (ret StatsPoint, ok bool) {
+	date := since.UTC().Format(StatsDateLayout)
+	for _, day := range h.Days {
+		point, exists := day.Repos[repo]
+		if !exists {
+			continue
+		}
+		if !ok || day.Date <= date {
+			ret, ok = point, true
+		}
+		if day.Date >= date {
+			break
+		}
+	}
+	return
+}
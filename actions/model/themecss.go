@@ -0,0 +1,227 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package model
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/gorilla/css/scanner"
+)
+
+// ThemeCSSEntry 主题的样式入口文件
+const ThemeCSSEntry = "theme.css"
+
+// 主题样式检查问题的严重程度，error 导致检查不通过，warning 仅供参考
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// maxThemeCSSFiles 从 theme.css 出发通过 @import 检查的本地文件数上限
+const maxThemeCSSFiles = 64
+
+// SiYuanCSSVariables 思源文档中列出的主题配色变量，主题应至少为其声明的每种模式覆盖其中之一
+var SiYuanCSSVariables = []string{
+	"--b3-theme-primary", "--b3-theme-primary-light", "--b3-theme-primary-lighter", "--b3-theme-primary-lightest",
+	"--b3-theme-secondary", "--b3-theme-background", "--b3-theme-background-light",
+	"--b3-theme-surface", "--b3-theme-surface-light", "--b3-theme-surface-lighter", "--b3-theme-error",
+	"--b3-theme-on-primary", "--b3-theme-on-secondary", "--b3-theme-on-background",
+	"--b3-theme-on-surface", "--b3-theme-on-surface-light", "--b3-theme-on-error",
+}
+
+// CSSIssue 主题样式检查发现的问题
+type CSSIssue struct {
+	File     string `json:"file"`     // 包内文件路径
+	Line     int    `json:"line"`     // 行号，从 1 开始，无法定位时为 0
+	Column   int    `json:"column"`   // 列号，从 1 开始，无法定位时为 0
+	Severity string `json:"severity"` // error 或 warning
+	Message  string `json:"message"`  // 问题描述
+}
+
+// CheckThemeCSS 对 theme.css 及其 @import 的本地文件做词法分析：报告词法错误、不匹配的括号、缺失的本地导入文件及远程导入，
+// 并在主题没有为 modes 中某个模式覆盖任何思源配色变量时给出警告；theme.css 不存在时不检查
+func (pz *PackageZip) CheckThemeCSS(modes []string) (ret []*CSSIssue) {
+	if !pz.Has(ThemeCSSEntry) {
+		return
+	}
+
+	overridden := map[string]bool{} // 覆盖了配色变量的模式，"" 表示未限定模式
+	queue := []string{ThemeCSSEntry}
+	visited := map[string]bool{ThemeCSSEntry: true}
+	for 0 < len(queue) {
+		file := queue[0]
+		queue = queue[1:]
+		data, err := pz.ReadFile(file)
+		if nil != err {
+			ret = append(ret, &CSSIssue{File: file, Severity: SeverityError, Message: err.Error()})
+			continue
+		}
+
+		issues, imports := scanThemeCSS(file, string(data), overridden)
+		ret = append(ret, issues...)
+		for _, imported := range imports {
+			if "" != imported.remote {
+				ret = append(ret, &CSSIssue{File: file, Line: imported.line, Column: imported.column, Severity: SeverityWarning,
+					Message: fmt.Sprintf("@import of remote URL %s is loaded at runtime and bypasses review", imported.remote)})
+				continue
+			}
+			if !pz.Has(imported.local) {
+				ret = append(ret, &CSSIssue{File: file, Line: imported.line, Column: imported.column, Severity: SeverityError,
+					Message: fmt.Sprintf("imported file %s does not exist in package.zip", imported.local)})
+				continue
+			}
+			if !visited[imported.local] && maxThemeCSSFiles > len(visited) {
+				visited[imported.local] = true
+				queue = append(queue, imported.local)
+			}
+		}
+	}
+
+	if 0 == len(modes) {
+		modes = []string{""}
+	}
+	for _, mode := range modes {
+		if overridden[""] || overridden[mode] {
+			continue
+		}
+		message := "does not override any documented SiYuan CSS variable (such as --b3-theme-primary)"
+		if "" != mode {
+			message = fmt.Sprintf("does not override any documented SiYuan CSS variable (such as --b3-theme-primary) for the %s mode", mode)
+		}
+		ret = append(ret, &CSSIssue{File: ThemeCSSEntry, Severity: SeverityWarning, Message: message})
+	}
+	return
+}
+
+// cssImport 样式文件中的一条 @import
+type cssImport struct {
+	local        string // 本地文件在包内的路径
+	remote       string // 远程 URL
+	line, column int
+}
+
+// scanThemeCSS 对单个样式文件做词法分析，返回发现的问题及 @import 的文件，覆盖了配色变量的模式记入 overridden
+func scanThemeCSS(file, content string, overridden map[string]bool) (issues []*CSSIssue, imports []*cssImport) {
+	s := scanner.New(content)
+	var blocks []string           // 嵌套块的选择器或 @ 规则前导
+	prelude := &strings.Builder{} // 当前块前导，遇到 { 时入栈
+	var pendingImport *scanner.Token
+	var prev *scanner.Token
+	parens := 0
+	for {
+		token := s.Next()
+		switch token.Type {
+		case scanner.TokenEOF:
+			if 0 < len(blocks) {
+				issues = append(issues, &CSSIssue{File: file, Line: token.Line, Column: token.Column, Severity: SeverityError, Message: fmt.Sprintf("%d unclosed {", len(blocks))})
+			}
+			return
+		case scanner.TokenError:
+			issues = append(issues, &CSSIssue{File: file, Line: token.Line, Column: token.Column, Severity: SeverityError, Message: token.Value})
+			return
+		case scanner.TokenComment, scanner.TokenS:
+			continue
+		}
+
+		if nil != pendingImport {
+			if target := cssImportTarget(token); "" != target {
+				imports = append(imports, resolveCSSImport(file, target, pendingImport))
+			}
+			pendingImport = nil
+		}
+
+		switch {
+		case scanner.TokenAtKeyword == token.Type && strings.EqualFold(token.Value, "@import"):
+			pendingImport = token
+		case scanner.TokenChar == token.Type && "(" == token.Value, scanner.TokenFunction == token.Type:
+			parens++
+		case scanner.TokenChar == token.Type && ")" == token.Value:
+			parens--
+		case scanner.TokenChar == token.Type && "{" == token.Value:
+			blocks = append(blocks, prelude.String())
+			prelude.Reset()
+			prev = token
+			continue
+		case scanner.TokenChar == token.Type && "}" == token.Value:
+			if 0 == len(blocks) {
+				issues = append(issues, &CSSIssue{File: file, Line: token.Line, Column: token.Column, Severity: SeverityError, Message: "unexpected }"})
+			} else {
+				blocks = blocks[:len(blocks)-1]
+			}
+			prelude.Reset()
+			prev = token
+			continue
+		case scanner.TokenChar == token.Type && ";" == token.Value:
+			prelude.Reset()
+			prev = token
+			continue
+		case scanner.TokenIdent == token.Type && strings.HasPrefix(token.Value, "-") &&
+			nil != prev && scanner.TokenChar == prev.Type && "-" == prev.Value && 0 < len(blocks):
+			// 自定义属性 --name 被切分为 - 与 -name 两个词法单元
+			if slices.Contains(SiYuanCSSVariables, "-"+token.Value) {
+				overridden[cssBlockMode(blocks)] = true
+			}
+		}
+		if 0 > parens {
+			issues = append(issues, &CSSIssue{File: file, Line: token.Line, Column: token.Column, Severity: SeverityError, Message: "unexpected )"})
+			parens = 0
+		}
+		prelude.WriteString(token.Value)
+		prelude.WriteString(" ")
+		prev = token
+	}
+}
+
+// cssImportTarget 返回 @import 后的导入目标，即字符串或 url()
+func cssImportTarget(token *scanner.Token) string {
+	switch token.Type {
+	case scanner.TokenString:
+		return token.Value[1 : len(token.Value)-1]
+	case scanner.TokenURI:
+		target := strings.TrimSpace(token.Value[len("url(") : len(token.Value)-1])
+		return strings.Trim(target, `"'`)
+	}
+	return ""
+}
+
+// resolveCSSImport 解析导入目标：带协议或以 // 开头的为远程 URL，其余为相对于导入文件的本地路径
+func resolveCSSImport(file, target string, at *scanner.Token) *cssImport {
+	ret := &cssImport{line: at.Line, column: at.Column}
+	lower := strings.ToLower(target)
+	if strings.HasPrefix(lower, "//") || strings.Contains(lower, "://") {
+		ret.remote = target
+		return ret
+	}
+	target, _, _ = strings.Cut(target, "?")
+	target, _, _ = strings.Cut(target, "#")
+	if strings.HasPrefix(target, "/") {
+		ret.local = strings.TrimPrefix(path.Clean(target), "/")
+	} else {
+		ret.local = strings.TrimPrefix(path.Clean("/"+path.Join(path.Dir(file), target)), "/")
+	}
+	return ret
+}
+
+// cssBlockMode 根据所在块的选择器判断声明作用的主题模式，未限定模式时返回空字符串
+func cssBlockMode(blocks []string) string {
+	for i := len(blocks) - 1; 0 <= i; i-- {
+		selector := strings.ReplaceAll(strings.ReplaceAll(blocks[i], " ", ""), "'", `"`)
+		for _, mode := range ThemeModes {
+			if strings.Contains(selector, `data-theme-mode="`+mode+`"`) || strings.Contains(selector, "data-theme-mode="+mode+"]") {
+				return mode
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,120 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package model
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+const modernThemeCSS = `@import url("./light.css");
+@layer base, theme;
+
+:root {
+  --b3-theme-primary: #3575f0;
+}
+
+@layer theme {
+  .protyle-wysiwyg {
+    & .p:has(> .hljs) {
+      color: var(--b3-theme-on-background);
+    }
+    &:hover { opacity: calc(1 - 0.1); }
+  }
+}
+
+@container sidebar (min-width: 400px) {
+  .layout-tab-bar { display: flex; }
+}
+
+@media (prefers-color-scheme: dark) {
+  :root { color-scheme: dark; }
+}
+`
+
+func TestCheckThemeCSS(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		modes []string
+		want  []string // "severity file:line message"
+	}{
+		{
+			name:  "modern css",
+			files: map[string]string{"theme.css": modernThemeCSS, "light.css": `:root { --b3-theme-background: #fff; }`},
+		},
+		{
+			name:  "unclosed brace",
+			files: map[string]string{"theme.css": ":root {\n  --b3-theme-primary: red;\n"},
+			want:  []string{"error theme.css:3 1 unclosed {"},
+		},
+		{
+			name:  "unexpected brace",
+			files: map[string]string{"theme.css": ":root { --b3-theme-primary: red; }\n}\n"},
+			want:  []string{"error theme.css:2 unexpected }"},
+		},
+		{
+			name:  "unexpected paren",
+			files: map[string]string{"theme.css": ":root { --b3-theme-primary: red); }"},
+			want:  []string{"error theme.css:1 unexpected )"},
+		},
+		{
+			name:  "missing local import",
+			files: map[string]string{"theme.css": "@import \"style/dark.css\";\n:root { --b3-theme-primary: red; }"},
+			want:  []string{"error theme.css:1 imported file style/dark.css does not exist in package.zip"},
+		},
+		{
+			name: "nested import resolved relative to the importing file",
+			files: map[string]string{
+				"theme.css":       `@import "style/base.css";`,
+				"style/base.css":  `@import url(../style/vars.css);`,
+				"style/vars.css":  `:root { --b3-theme-primary: red; }`,
+				"style/other.css": `}`,
+			},
+		},
+		{
+			name:  "remote import",
+			files: map[string]string{"theme.css": "@import url(https://example.com/a.css);\n:root { --b3-theme-primary: red; }"},
+			want:  []string{"warning theme.css:1 @import of remote URL https://example.com/a.css is loaded at runtime and bypasses review"},
+		},
+		{
+			name:  "no siyuan variables",
+			files: map[string]string{"theme.css": ":root { --my-color: red; }"},
+			want:  []string{"warning theme.css:0 does not override any documented SiYuan CSS variable (such as --b3-theme-primary)"},
+		},
+		{
+			name:  "missing mode",
+			files: map[string]string{"theme.css": `html[data-theme-mode="light"] { --b3-theme-primary: red; }`},
+			modes: []string{"light", "dark"},
+			want:  []string{"warning theme.css:0 does not override any documented SiYuan CSS variable (such as --b3-theme-primary) for the dark mode"},
+		},
+		{
+			name:  "no theme.css",
+			files: map[string]string{"style.css": "}"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pz, err := OpenPackageZip(newTestZip(t, tt.files))
+			if nil != err {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, issue := range pz.CheckThemeCSS(tt.modes) {
+				got = append(got, issue.Severity+" "+issue.File+":"+strconv.Itoa(issue.Line)+" "+issue.Message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckThemeCSS() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
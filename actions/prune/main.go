@@ -0,0 +1,240 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/88250/gulu"
+	"github.com/panjf2000/ants/v2"
+	"github.com/parnurzeal/gorequest"
+	"github.com/siyuan-note/bazaar/actions/model"
+	"github.com/siyuan-note/bazaar/actions/util"
+)
+
+var logger = gulu.Log.NewLogger(os.Stdout)
+
+// defaultStaleDays 最新发布超过该天数视为长期未维护，可通过环境变量 PRUNE_STALE_DAYS 修改，0 表示不检查
+const defaultStaleDays = 730
+
+// 条目被清理的原因
+const (
+	reasonRepoMissing       = "repository not found"
+	reasonReleaseMissing    = "no release"
+	reasonPackageZipMissing = "no `package.zip` in the latest release"
+	reasonStale             = "latest release is older than %d days (%s)"
+)
+
+// prunedRepo 一条被清理的条目
+type prunedRepo struct {
+	repo   string
+	reason string
+}
+
+// listSummary 一个列表文件的清理结果
+type listSummary struct {
+	listPath string
+	total    int
+	pruned   []*prunedRepo
+	failed   []*prunedRepo // 查询失败未能判断的条目，保留在列表中
+}
+
+// main 检查 *.txt 包列表中的每个条目，删除仓库不存在、没有发布、最新发布没有 package.zip 或长期未发布的条目，
+// 改写列表文件并输出 Markdown 摘要（PRUNE_SUMMARY_OUTPUT，默认为 prune-summary.md），可直接用于提交清理 PR
+func main() {
+	logger.Infof("bazaar is pruning...")
+
+	staleDays := defaultStaleDays
+	if value := os.Getenv("PRUNE_STALE_DAYS"); "" != value {
+		days, err := strconv.Atoi(value)
+		if nil != err || 0 > days {
+			logger.Fatalf("invalid PRUNE_STALE_DAYS [%s]", value)
+		}
+		staleDays = days
+	}
+
+	var summaries []*listSummary
+	for _, typ := range model.Types {
+		summaries = append(summaries, prune(typ+".txt", staleDays))
+	}
+
+	output := os.Getenv("PRUNE_SUMMARY_OUTPUT")
+	if "" == output {
+		output = "prune-summary.md"
+	}
+	if err := os.WriteFile(output, []byte(renderSummary(summaries, staleDays)), 0644); nil != err {
+		logger.Fatalf("write prune summary [%s] failed: %s", output, err)
+	}
+
+	logger.Infof("pruned bazaar")
+}
+
+// prune 检查列表文件中的所有条目并删除失效的条目
+func prune(listPath string, staleDays int) (ret *listSummary) {
+	ret = &listSummary{listPath: listPath}
+	repos, err := util.ParseReposFromTxt(listPath)
+	if nil != err {
+		logger.Fatalf("parse [%s] failed: %s", listPath, err)
+	}
+	ret.total = len(repos)
+
+	prunedSet := map[string]bool{}
+	lock := sync.Mutex{}
+	waitGroup := &sync.WaitGroup{}
+	p, _ := ants.NewPoolWithFunc(8, func(arg interface{}) {
+		defer waitGroup.Done()
+		entry := arg.(*util.RepoEntry)
+		repo := entry.Repo
+		reason, evalErr := evaluate(entry, staleDays)
+
+		lock.Lock()
+		defer lock.Unlock()
+		if nil != evalErr {
+			logger.Warnf("evaluate [%s] failed: %s", repo, evalErr)
+			ret.failed = append(ret.failed, &prunedRepo{repo: repo, reason: evalErr.Error()})
+			return
+		}
+		if "" != reason {
+			logger.Infof("[%s] prune [%s]: %s", listPath, repo, reason)
+			ret.pruned = append(ret.pruned, &prunedRepo{repo: repo, reason: reason})
+			prunedSet[repo] = true
+		}
+	})
+	defer p.Release()
+	for _, entry := range repos {
+		waitGroup.Add(1)
+		p.Invoke(entry)
+	}
+	waitGroup.Wait()
+
+	sort.Slice(ret.pruned, func(i, j int) bool { return ret.pruned[i].repo < ret.pruned[j].repo })
+	sort.Slice(ret.failed, func(i, j int) bool { return ret.failed[i].repo < ret.failed[j].repo })
+	if 0 == len(prunedSet) {
+		return
+	}
+
+	err = util.RewriteReposTxt(listPath, func(entry *util.RepoEntry) string {
+		if prunedSet[entry.Repo] {
+			return ""
+		}
+		return entry.Repo
+	})
+	if nil != err {
+		logger.Fatalf("rewrite [%s] failed: %s", listPath, err)
+	}
+	return
+}
+
+// evaluate 判断条目是否应被清理，返回清理原因，应保留时返回空字符串；查询失败时返回错误，条目保留。
+// 标注了 pinned 的条目检查指定 tag 的发布，且不检查是否长期未发布
+func evaluate(entry *util.RepoEntry, staleDays int) (reason string, err error) {
+	repo := entry.Repo
+	_, notFound, err := util.GetCanonicalRepo(repo)
+	if nil != err {
+		return
+	}
+	if notFound {
+		reason = reasonRepoMissing
+		return
+	}
+
+	result := &struct {
+		PublishedAt string `json:"published_at"`
+		Assets      []struct {
+			Name string `json:"name"`
+		} `json:"assets"`
+	}{}
+	// REF https://docs.github.com/en/rest/releases/releases#get-the-latest-release
+	u := "https://api.github.com/repos/" + repo + "/releases/latest"
+	if tag := entry.Attr(util.AttrPinned); "" != tag {
+		// REF https://docs.github.com/en/rest/releases/releases#get-a-release-by-tag-name
+		u = "https://api.github.com/repos/" + repo + "/releases/tags/" + url.PathEscape(tag)
+		staleDays = 0
+	}
+	resp, _, errs := gorequest.New().Get(u).
+		Set("Authorization", "Token "+os.Getenv("PAT")).
+		Set("User-Agent", util.UserAgent).
+		Retry(1, 3*time.Second).Timeout(30 * time.Second).EndStruct(result)
+	if nil != errs {
+		err = fmt.Errorf("get [%s] failed: %s", u, errs)
+		return
+	}
+	if http.StatusNotFound == resp.StatusCode {
+		reason = reasonReleaseMissing
+		return
+	}
+	if http.StatusOK != resp.StatusCode {
+		err = fmt.Errorf("get [%s] failed: %d", u, resp.StatusCode)
+		return
+	}
+
+	hasPackageZip := false
+	for _, asset := range result.Assets {
+		if "package.zip" == asset.Name {
+			hasPackageZip = true
+			break
+		}
+	}
+	if !hasPackageZip {
+		reason = reasonPackageZipMissing
+		return
+	}
+
+	if 0 < staleDays {
+		published, parseErr := time.Parse(time.RFC3339, result.PublishedAt)
+		if nil != parseErr {
+			err = fmt.Errorf("parse published time [%s] failed: %s", result.PublishedAt, parseErr)
+			return
+		}
+		if time.Since(published) > time.Duration(staleDays)*24*time.Hour {
+			reason = fmt.Sprintf(reasonStale, staleDays, published.Format("2006-01-02"))
+		}
+	}
+	return
+}
+
+// renderSummary 输出清理摘要，可直接作为清理 PR 的描述
+func renderSummary(summaries []*listSummary, staleDays int) string {
+	buf := &strings.Builder{}
+	buf.WriteString("## Prune dead package list entries\n\n")
+	buf.WriteString("Entries are removed when the repository is not found, it has no release, the latest release has no `package.zip`")
+	if 0 < staleDays {
+		buf.WriteString(fmt.Sprintf(", or the latest release is older than %d days", staleDays))
+	}
+	buf.WriteString(".\n\n")
+
+	for _, summary := range summaries {
+		buf.WriteString(fmt.Sprintf("### `%s`: removed %d of %d\n\n", summary.listPath, len(summary.pruned), summary.total))
+		if 0 < len(summary.pruned) {
+			buf.WriteString("| Repository | Reason |\n| --- | --- |\n")
+			for _, pruned := range summary.pruned {
+				buf.WriteString(fmt.Sprintf("| [%s](https://github.com/%s) | %s |\n", pruned.repo, pruned.repo, pruned.reason))
+			}
+			buf.WriteString("\n")
+		}
+		if 0 < len(summary.failed) {
+			buf.WriteString("Not evaluated, kept in the list:\n\n")
+			for _, failed := range summary.failed {
+				buf.WriteString(fmt.Sprintf("- [%s](https://github.com/%s): %s\n", failed.repo, failed.repo, failed.reason))
+			}
+			buf.WriteString("\n")
+		}
+	}
+	return buf.String()
+}
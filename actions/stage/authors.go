@@ -0,0 +1,102 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/siyuan-note/bazaar/actions/model"
+)
+
+// stageAuthors 按 GitHub owner 汇总所有包生成 stage/authors.json
+func stageAuthors(stageFiles map[string]*model.StageFile) {
+	logger.Infof("staging [%s]", model.AuthorsName)
+
+	authors := map[string]*model.Author{}
+	names := map[string][]string{}
+	for _, typ := range model.Types {
+		stageFile := stageFiles[typ]
+		if nil == stageFile {
+			continue
+		}
+		for _, repo := range stageFile.Repos {
+			repoPath := repo.RepoPath()
+			if "" == repoPath || nil == repo.Package {
+				continue
+			}
+			owner, _, ok := strings.Cut(repoPath, "/")
+			if !ok {
+				continue
+			}
+			author := authors[owner]
+			if nil == author {
+				author = &model.Author{Owner: owner, Names: []string{}, Counts: map[string]int{}, Funding: []string{}}
+				authors[owner] = author
+			}
+
+			pkg := repo.Package.Base()
+			author.Packages = append(author.Packages, &model.AuthorPackage{Type: typ, Repo: repoPath, Name: pkg.Name, Stars: repo.Stars})
+			author.Stars += repo.Stars
+			author.Total++
+			author.Counts[typ]++
+			if repo.Updated > author.Updated {
+				author.Updated = repo.Updated
+			}
+			if name := strings.TrimSpace(pkg.Author); "" != name {
+				author.Names = appendUnique(author.Names, name)
+				names[name] = appendUnique(names[name], owner)
+			}
+			for _, link := range pkg.Funding.Links() {
+				author.Funding = appendUnique(author.Funding, link)
+			}
+		}
+	}
+
+	index := &model.AuthorsIndex{Authors: make([]*model.Author, 0, len(authors)), Names: names}
+	for _, author := range authors {
+		sort.Strings(author.Names)
+		sort.Strings(author.Funding)
+		sort.SliceStable(author.Packages, func(i, j int) bool {
+			if author.Packages[i].Type != author.Packages[j].Type {
+				return author.Packages[i].Type < author.Packages[j].Type
+			}
+			return author.Packages[i].Repo < author.Packages[j].Repo
+		})
+		index.Authors = append(index.Authors, author)
+	}
+	for _, owners := range names {
+		sort.Strings(owners)
+	}
+	sort.Slice(index.Authors, func(i, j int) bool {
+		oi, oj := strings.ToLower(index.Authors[i].Owner), strings.ToLower(index.Authors[j].Owner)
+		if oi != oj {
+			return oi < oj
+		}
+		return index.Authors[i].Owner < index.Authors[j].Owner
+	})
+
+	if err := model.SaveAuthorsIndex("stage/"+model.AuthorsName+".json", index); nil != err {
+		logger.Fatalf("write stage [%s.json] failed: %s", model.AuthorsName, err)
+	}
+
+	logger.Infof("staged [%s]", model.AuthorsName)
+}
+
+// appendUnique 追加 value，已存在时不重复追加
+func appendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
@@ -0,0 +1,64 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/siyuan-note/bazaar/actions/model"
+	"github.com/siyuan-note/bazaar/actions/util"
+)
+
+// stageCatalog 汇总所有类型的 stage 数据生成 stage/bazaar.json
+func stageCatalog(stageFiles map[string]*model.StageFile) {
+	logger.Infof("staging [%s]", model.CatalogName)
+
+	catalog := &model.Catalog{
+		SchemaVersion: model.CatalogSchemaVersion,
+		Generator:     util.UserAgent,
+		Counts:        map[string]int{},
+		Repos:         []*model.CatalogRepo{},
+	}
+	for _, typ := range model.Types {
+		stageFile := stageFiles[typ]
+		if nil == stageFile {
+			continue
+		}
+		for _, repo := range stageFile.Repos {
+			catalog.Repos = append(catalog.Repos, &model.CatalogRepo{Type: typ, StageRepo: repo})
+		}
+		catalog.Counts[typ] = len(stageFile.Repos)
+		catalog.Total += len(stageFile.Repos)
+	}
+	sort.SliceStable(catalog.Repos, func(i, j int) bool {
+		return catalog.Repos[i].Updated > catalog.Repos[j].Updated
+	})
+
+	// 包数据无变化时沿用旧的生成时间，避免每小时产生无意义的提交
+	catalogPath := "stage/" + model.CatalogName + ".json"
+	catalog.Generated = time.Now().UTC().Format(time.RFC3339)
+	if oldCatalog, err := model.LoadCatalog(catalogPath); nil == err {
+		oldData, _ := json.Marshal(oldCatalog.Repos)
+		newData, _ := json.Marshal(catalog.Repos)
+		if oldCatalog.SchemaVersion == catalog.SchemaVersion && bytes.Equal(oldData, newData) {
+			catalog.Generated = oldCatalog.Generated
+		}
+	}
+
+	if err := model.SaveCatalog(catalogPath, catalog); nil != err {
+		logger.Fatalf("write stage [%s.json] failed: %s", model.CatalogName, err)
+	}
+
+	logger.Infof("staged [%s]", model.CatalogName)
+}
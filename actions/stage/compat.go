@@ -0,0 +1,198 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package main
+
+import (
+	"crypto/tls"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/parnurzeal/gorequest"
+	"github.com/siyuan-note/bazaar/actions/model"
+	"github.com/siyuan-note/bazaar/actions/util"
+)
+
+// stageCompat 将本次见到的最新发布版本并入发布历史，生成按思源 minor 版本查询兼容包版本的 stage/compat.json
+func stageCompat(stageFiles map[string]*model.StageFile) {
+	logger.Infof("staging [%s]", model.CompatName)
+
+	compatPath := "stage/" + model.CompatName + ".json"
+	index, err := model.LoadCompatIndex(compatPath)
+	if nil != err {
+		logger.Warnf("load [%s] failed, rebuild it: %s", compatPath, err)
+		index = &model.CompatIndex{Packages: map[string]*model.CompatPackage{}}
+	}
+
+	// 合并发布历史，已从列表中移除的包一并移除
+	packages := map[string]*model.CompatPackage{}
+	for _, typ := range model.Types {
+		stageFile := stageFiles[typ]
+		if nil == stageFile {
+			continue
+		}
+		for _, repo := range stageFile.Repos {
+			repoPath := repo.RepoPath()
+			if "" == repoPath || nil == repo.Package {
+				continue
+			}
+			pkg := repo.Package.Base()
+			for field, value := range map[string]string{"minAppVersion": pkg.MinAppVersion, "maxAppVersion": pkg.MaxAppVersion} {
+				if _, ok := model.ParseVersion(value); "" != value && !ok {
+					stageReport.add("Invalid app versions", "[%s](https://github.com/%s): `%s` `%s` is not a semantic version", repoPath, repoPath, field, value)
+				}
+			}
+
+			compatPkg := index.Packages[repoPath]
+			if nil == compatPkg {
+				compatPkg = &model.CompatPackage{}
+			}
+			compatPkg.Type = typ
+			compatPkg.Releases = mergeCompatRelease(compatPkg.Releases, &model.CompatRelease{
+				Version:       pkg.Version,
+				Hash:          repo.Hash(),
+				MinAppVersion: pkg.MinAppVersion,
+				MaxAppVersion: pkg.MaxAppVersion,
+				Updated:       repo.Updated,
+			})
+			packages[repoPath] = compatPkg
+		}
+	}
+	appVersion := latestAppVersion()
+	if "" == appVersion && 0 < len(index.AppMinors) {
+		// 获取失败时沿用上次覆盖到的最高版本，避免 minor 版本集合缩小导致 compat.json 无意义地变化
+		appVersion = index.AppMinors[len(index.AppMinors)-1]
+	}
+	index.Packages = packages
+	index.AppMinors = compatAppMinors(packages, appVersion)
+	for _, compatPkg := range packages {
+		compatPkg.Compatible = map[string]string{}
+		for _, appMinor := range index.AppMinors {
+			appMinorVer, _ := model.ParseVersion(appMinor)
+			if release := newestCompatRelease(compatPkg.Releases, appMinorVer); nil != release {
+				compatPkg.Compatible[appMinor] = release.Version
+			}
+		}
+	}
+
+	if err = model.SaveCompatIndex(compatPath, index); nil != err {
+		logger.Fatalf("write stage [%s.json] failed: %s", model.CompatName, err)
+	}
+
+	logger.Infof("staged [%s]", model.CompatName)
+}
+
+// mergeCompatRelease 将发布版本并入历史（同一提交只保留一份，以最新数据为准），按发布时间降序并截断
+func mergeCompatRelease(releases []*model.CompatRelease, release *model.CompatRelease) []*model.CompatRelease {
+	ret := []*model.CompatRelease{release}
+	for _, r := range releases {
+		if r.Hash != release.Hash {
+			ret = append(ret, r)
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Updated > ret[j].Updated
+	})
+	if model.CompatReleaseLimit < len(ret) {
+		ret = ret[:model.CompatReleaseLimit]
+	}
+	return ret
+}
+
+// compatAppMinors 收集发布历史中出现的思源 minor 版本，同一 major 下补齐最小与最大 minor 之间的版本，
+// 并补齐到思源当前发布版本 appVersion 所在的 minor，使比所有包声明的版本都新的客户端也能查到兼容版本
+func compatAppMinors(packages map[string]*model.CompatPackage, appVersion string) (ret []string) {
+	minorRange := map[int][2]int{} // major -> [最小 minor, 最大 minor]
+	add := func(value string) {
+		v, ok := model.ParseVersion(value)
+		if !ok {
+			return
+		}
+		r, exists := minorRange[v.Major]
+		if !exists {
+			minorRange[v.Major] = [2]int{v.Minor, v.Minor}
+			return
+		}
+		r[0], r[1] = min(r[0], v.Minor), max(r[1], v.Minor)
+		minorRange[v.Major] = r
+	}
+	for _, compatPkg := range packages {
+		for _, release := range compatPkg.Releases {
+			add(release.MinAppVersion)
+			add(release.MaxAppVersion)
+		}
+	}
+
+	if v, ok := model.ParseVersion(appVersion); ok {
+		r, exists := minorRange[v.Major]
+		if !exists {
+			r = [2]int{0, v.Minor} // 新的 major 从 0 开始
+		}
+		r[1] = max(r[1], v.Minor)
+		minorRange[v.Major] = r
+	}
+
+	var versions []model.Version
+	for major, r := range minorRange {
+		for minor := r[0]; minor <= r[1]; minor++ {
+			versions = append(versions, model.Version{Major: major, Minor: minor})
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return 0 > versions[i].Compare(versions[j])
+	})
+	for _, v := range versions {
+		ret = append(ret, v.MinorString())
+	}
+	return
+}
+
+// newestCompatRelease 返回支持 appMinor 的最新包版本（按包版本号比较，版本号不合法时按发布时间）
+func newestCompatRelease(releases []*model.CompatRelease, appMinor model.Version) (ret *model.CompatRelease) {
+	for _, release := range releases {
+		if !release.Supports(appMinor) {
+			continue
+		}
+		if nil == ret {
+			ret = release
+			continue
+		}
+		v, ok := model.ParseVersion(release.Version)
+		retV, retOk := model.ParseVersion(ret.Version)
+		if ok && retOk && 0 < v.Compare(retV) {
+			ret = release
+		}
+	}
+	return
+}
+
+// latestAppVersion 获取思源当前发布的版本号，获取失败时返回空字符串
+func latestAppVersion() string {
+	result := &struct {
+		TagName string `json:"tag_name"`
+	}{}
+	request := gorequest.New().TLSClientConfig(&tls.Config{InsecureSkipVerify: true})
+	// REF https://docs.github.com/en/rest/releases/releases#get-the-latest-release
+	u := "https://api.github.com/repos/siyuan-note/siyuan/releases/latest"
+	resp, _, errs := request.Get(u).
+		Set("Authorization", "Token "+os.Getenv("PAT")).
+		Set("User-Agent", util.UserAgent).Timeout(30*time.Second).
+		Retry(1, 3*time.Second).EndStruct(result)
+	if nil != errs {
+		logger.Warnf("get [%s] failed: %s", u, errs)
+		return ""
+	}
+	if 200 != resp.StatusCode {
+		logger.Warnf("get [%s] failed: %d", u, resp.StatusCode)
+		return ""
+	}
+	return result.TagName
+}
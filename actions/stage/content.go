@@ -0,0 +1,43 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/siyuan-note/bazaar/actions/model"
+)
+
+// checkPackageContent 检查 package.zip 的内容，发现的问题记入运行报告，不影响包上架
+func checkPackageContent(repoURL, typ string, data []byte, pkg model.Manifest) {
+	packageZip, err := model.OpenPackageZip(data)
+	if nil != err {
+		stageReport.add("Invalid package.zip", "[%s](https://github.com/%s): %s", repoURL, repoURL, err)
+		return
+	}
+
+	if syntaxErr := packageZip.CheckJSSyntax(typ); nil != syntaxErr {
+		stageReport.add("JavaScript syntax errors", "[%s](https://github.com/%s): `%s` line %d, column %d: %s", repoURL, repoURL, syntaxErr.File, syntaxErr.Line, syntaxErr.Column, syntaxErr.Message)
+	}
+
+	for _, finding := range packageZip.ScanJSSecurity(typ) {
+		stageReport.add("Plugin security findings", "[%s](https://github.com/%s): %s `%s` line %d (%d matches): %s: `%s`", repoURL, repoURL, finding.Severity, finding.File, finding.Line, finding.Count, finding.Message, finding.Evidence)
+	}
+	if theme, ok := pkg.(*model.ThemePackage); ok {
+		for _, issue := range packageZip.CheckThemeCSS(theme.Modes) {
+			position := ""
+			if 0 < issue.Line {
+				position = fmt.Sprintf(" line %d, column %d", issue.Line, issue.Column)
+			}
+			stageReport.add("Theme CSS issues", "[%s](https://github.com/%s): %s `%s`%s: %s", repoURL, repoURL, issue.Severity, issue.File, position, issue.Message)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"strings"
+	"sync"
+
+	"github.com/HugoSmits86/nativewebp"
+	"github.com/siyuan-note/bazaar/actions/model"
+	"github.com/siyuan-note/bazaar/actions/util"
+	"golang.org/x/image/draw"
+)
+
+// previewThumbnailBoxes 预览图缩略图的限定尺寸（预览图要求为 1024×768）
+var previewThumbnailBoxes = [][2]int{{512, 384}, {256, 192}}
+
+// maxImagePixels 解码图片的像素数上限，防止解压炸弹
+const maxImagePixels = 4096 * 4096
+
+// indexPackageImage 上传预览图或图标原图，并生成、上传其 WebP 变体，boxes 不为空时还生成对应尺寸的缩略图
+func indexPackageImage(ownerRepo, hash, filePath string, boxes [][2]int, variants *[]*model.ImageVariant, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	u := "https://raw.githubusercontent.com/" + ownerRepo + "/" + hash + filePath
+	data, ok := getPackageFile(u)
+	if !ok {
+		return
+	}
+
+	key := "package/" + ownerRepo + "@" + hash + filePath
+	if err := util.UploadOSS(key, "", data); nil != err {
+		logger.Errorf("upload package file [%s] failed: %s", key, err)
+		return
+	}
+
+	img, err := decodeImage(data)
+	if nil != err {
+		stageReport.add("Invalid images", "[%s](https://github.com/%s): `%s` %s", ownerRepo, ownerRepo, strings.TrimPrefix(filePath, "/"), err)
+		return
+	}
+
+	original := strings.TrimPrefix(filePath, "/")
+	upload := func(img image.Image, box, format string) {
+		buf := &bytes.Buffer{}
+		contentType := "image/" + format
+		var err error
+		if model.ImageFormatWebP == format {
+			err = nativewebp.Encode(buf, img, nil)
+		} else {
+			err = (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(buf, img)
+		}
+		if nil != err {
+			logger.Errorf("encode [%s/%s] %s variant failed: %s", ownerRepo, original, format, err)
+			return
+		}
+
+		variant := &model.ImageVariant{
+			Path:   model.ImageVariantPath(original, box, format),
+			Width:  img.Bounds().Dx(),
+			Height: img.Bounds().Dy(),
+			Format: format,
+		}
+		variantKey := "package/" + ownerRepo + "@" + hash + "/" + variant.Path
+		if err = util.UploadOSS(variantKey, contentType, buf.Bytes()); nil != err {
+			logger.Errorf("upload package file [%s] failed: %s", variantKey, err)
+			return
+		}
+		*variants = append(*variants, variant)
+	}
+
+	upload(img, "", model.ImageFormatWebP)
+	for _, box := range boxes {
+		thumbnail := thumbnailImage(img, box[0], box[1])
+		if nil == thumbnail {
+			continue
+		}
+		name := fmt.Sprintf("%dx%d", box[0], box[1])
+		upload(thumbnail, name, model.ImageFormatPNG)
+		upload(thumbnail, name, model.ImageFormatWebP)
+	}
+}
+
+// decodeImage 解码图片，先检查尺寸以拒绝过大的图片
+func decodeImage(data []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if nil != err {
+		return nil, fmt.Errorf("decode failed: %s", err)
+	}
+	if 1 > cfg.Width || 1 > cfg.Height || maxImagePixels < cfg.Width*cfg.Height {
+		return nil, fmt.Errorf("size %dx%d is not supported", cfg.Width, cfg.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if nil != err {
+		return nil, fmt.Errorf("decode failed: %s", err)
+	}
+	return img, nil
+}
+
+// thumbnailImage 等比缩放图片到 width×height 以内，图片不大于该尺寸时返回 nil（不放大）
+func thumbnailImage(img image.Image, width, height int) image.Image {
+	bounds := img.Bounds()
+	if bounds.Dx() <= width && bounds.Dy() <= height {
+		return nil
+	}
+
+	scale := min(float64(width)/float64(bounds.Dx()), float64(height)/float64(bounds.Dy()))
+	w := max(1, int(float64(bounds.Dx())*scale+0.5))
+	h := max(1, int(float64(bounds.Dy())*scale+0.5))
+	ret := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(ret, ret.Bounds(), img, bounds, draw.Src, nil)
+	return ret
+}
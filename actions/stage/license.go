@@ -0,0 +1,38 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package main
+
+import (
+	"github.com/siyuan-note/bazaar/actions/model"
+)
+
+// detectLicense 识别包的 SPDX 许可证：优先使用发行版提交时仓库根目录下的许可证文件，其次使用 package.zip 中的许可证文件，
+// 均无法识别时记入运行报告并返回空字符串
+func detectLicense(ownerRepo, hash string, data []byte) string {
+	for _, name := range model.LicenseFileNames {
+		licenseData, ok := getPackageFile("https://raw.githubusercontent.com/" + ownerRepo + "/" + hash + "/" + name)
+		if !ok {
+			continue
+		}
+		if id := model.DetectLicense(licenseData); "" != id {
+			return id
+		}
+	}
+
+	if packageZip, err := model.OpenPackageZip(data); nil == err {
+		if _, id := packageZip.DetectLicense(); "" != id {
+			return id
+		}
+	}
+
+	stageReport.add("Missing licenses", "[%s](https://github.com/%s): no recognized license in `LICENSE` or package.zip", ownerRepo, ownerRepo)
+	return ""
+}
@@ -12,6 +12,7 @@ package main
 
 import (
 	"crypto/tls"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sort"
@@ -23,6 +24,7 @@ import (
 	"github.com/microcosm-cc/bluemonday"
 	"github.com/panjf2000/ants/v2"
 	"github.com/parnurzeal/gorequest"
+	"github.com/siyuan-note/bazaar/actions/model"
 	"github.com/siyuan-note/bazaar/actions/util"
 )
 
@@ -31,136 +33,128 @@ var (
 	sterilizer = bluemonday.UGCPolicy()
 )
 
+// renamedRepoSection 运行报告中记录改名或转移仓库的章节
+const renamedRepoSection = "Renamed repositories (run `go run ./actions/canonicalize` to update the lists)"
+
 func main() {
 	logger.Infof("bazaar is staging...")
 
-	performStage("themes")
-	performStage("templates")
-	performStage("icons")
-	performStage("widgets")
-	performStage("plugins")
+	loadStats()
+	stageFiles := map[string]*model.StageFile{}
+	for _, typ := range model.Types {
+		stageFiles[typ] = performStage(typ)
+	}
+	saveStats()
+	stageCatalog(stageFiles)
+	stageSearchIndexes(stageFiles)
+	stageAuthors(stageFiles)
+	stageTags(stageFiles)
+	stageCompat(stageFiles)
+	stageReport.write()
 
 	logger.Infof("bazaar staged")
 }
 
 // loadOldStageData 加载现有的 stage 文件数据，返回以 owner/repo 为 key 的映射
-func loadOldStageData(typ string) map[string]*StageRepo {
-	oldStageData := make(map[string]*StageRepo)
-	stageFilePath := "stage/" + typ + ".json"
-
-	stageData, err := os.ReadFile(stageFilePath)
+func loadOldStageData(typ string) map[string]*model.StageRepo {
+	stageFile, err := model.LoadStageFile("stage/"+typ+".json", typ)
 	if nil != err {
-		return oldStageData
-	}
-
-	oldStaged := map[string]interface{}{}
-	if err = gulu.JSON.UnmarshalJSON(stageData, &oldStaged); nil != err {
-		return oldStageData
-	}
-
-	oldRepos, ok := oldStaged["repos"].([]interface{})
-	if !ok {
-		return oldStageData
-	}
-
-	for _, repo := range oldRepos {
-		repoMap, ok := repo.(map[string]interface{})
-		if !ok {
-			continue
-		}
-
-		url, ok := repoMap["url"].(string)
-		if !ok {
-			continue
-		}
-
-		// 从 URL 中提取 owner/repo（去掉 @hash 部分）
-		idx := strings.Index(url, "@")
-		if idx <= 0 {
-			continue
-		}
-
-		repoKey := url[:idx]
-		stageRepo := &StageRepo{}
-		repoJSON, marshalErr := gulu.JSON.MarshalJSON(repoMap)
-		if nil != marshalErr {
-			continue
-		}
-
-		if err = gulu.JSON.UnmarshalJSON(repoJSON, stageRepo); nil != err {
-			continue
+		if !os.IsNotExist(err) {
+			logger.Warnf("load old stage [%s.json] failed: %s", typ, err)
 		}
-
-		oldStageData[repoKey] = stageRepo
+		return map[string]*model.StageRepo{}
 	}
-
-	return oldStageData
+	return stageFile.RepoMap()
 }
 
-func performStage(typ string) {
+func performStage(typ string) *model.StageFile {
 	logger.Infof("staging [%s]", typ)
 
 	reposSlice, err := util.ParseReposFromTxt(typ + ".txt")
 	if nil != err {
 		logger.Fatalf("read or parse [%s.txt] failed: %s", typ, err)
 	}
-	// 与后续 Invoke(arg) 的 arg.(string) 兼容，转为 []interface{}
+	// 与后续 Invoke(arg) 的 arg.(*util.RepoEntry) 兼容，转为 []interface{}
 	repos := make([]interface{}, len(reposSlice))
-	for i, s := range reposSlice {
-		repos[i] = s
+	for i, entry := range reposSlice {
+		repos[i] = entry
 	}
 
 	oldStageData := loadOldStageData(typ)
 
 	lock := sync.Mutex{}
-	var stageRepos []interface{}
+	var stageRepos []*model.StageRepo
 	waitGroup := &sync.WaitGroup{}
 
 	p, _ := ants.NewPoolWithFunc(8, func(arg interface{}) {
 		defer waitGroup.Done()
-		repo := arg.(string)
-		var hash, updated string
-		var size, installSize int64
-		var ok bool
-		var pkg interface{}
-
-		ok, hash, updated, size, installSize, pkg = indexPackage(repo, typ)
-		if !ok || pkg == nil {
-			// 索引失败或 pkg 为空时使用旧数据，避免 "package": null 的坏数据覆盖
+		entry := arg.(*util.RepoEntry)
+		repo := entry.Repo
+		if entry.Has(util.AttrHidden) {
+			stageReport.add("Hidden repositories", "[%s](https://github.com/%s) (`%s.txt` line %d)", repo, repo, typ, entry.Line)
+			return
+		}
+		// 先检查仓库状态，不能上架的仓库不索引，避免向 OSS 上传其资源
+		stars, openIssues, fullName, health, unavailable, ok := repoStats(repo)
+		if "" != unavailable {
+			// 仓库已删除、转为私有或被 GitHub 屏蔽，不能沿用旧数据
+			stageReport.add("Excluded repositories", "[%s](https://github.com/%s): %s", repo, repo, unavailable)
+			return
+		}
+		// 如果获取统计数据失败，尝试使用旧数据
+		if !ok {
 			lock.Lock()
 			if oldRepo, exists := oldStageData[repo]; exists {
 				stageRepos = append(stageRepos, oldRepo)
-				logger.Warnf("index failed for [%s], keeping old data", repo)
+				logger.Warnf("repoStats failed for [%s], keeping old data", repo)
 			} else {
-				logger.Warnf("index failed for [%s] and no old data found", repo)
+				logger.Warnf("repoStats failed for [%s] and no old data found", repo)
 			}
 			lock.Unlock()
 			return
 		}
 
-		stars, openIssues, ok := repoStats(repo)
-		// 如果获取统计数据失败，尝试使用旧数据
-		if !ok {
+		if "" != fullName && !strings.EqualFold(fullName, repo) {
+			// GitHub 对改名或转移的仓库重定向旧名称，列表中的旧名称仍可使用，但需要更新；仅大小写不同时与 check 一致，不视为改名
+			stageReport.add(renamedRepoSection, "`%s` → [%s](https://github.com/%s)", repo, fullName, fullName)
+		}
+		if reason := health.ExcludeReason(); "" != reason {
+			stageReport.add("Excluded repositories", "[%s](https://github.com/%s): %s", repo, repo, reason)
+			return
+		}
+		if health.Archived {
+			stageReport.add("Archived repositories", "[%s](https://github.com/%s)", repo, repo)
+		}
+
+		ok, hash, updated, size, installSize, pkg, images, readme, license := indexPackage(repo, typ, entry.Attr(util.AttrPinned), oldStageData[repo])
+		if !ok || pkg == nil {
+			// 索引失败或 pkg 为空时使用旧数据，避免 "package": null 的坏数据覆盖
 			lock.Lock()
 			if oldRepo, exists := oldStageData[repo]; exists {
 				stageRepos = append(stageRepos, oldRepo)
-				logger.Warnf("repoStats failed for [%s], keeping old data", repo)
+				logger.Warnf("index failed for [%s], keeping old data", repo)
 			} else {
-				logger.Warnf("repoStats failed for [%s] and no old data found", repo)
+				logger.Warnf("index failed for [%s] and no old data found", repo)
 			}
 			lock.Unlock()
 			return
 		}
 
+		sampleStats(repo, stars, size)
+
 		lock.Lock()
 		defer lock.Unlock()
-		stageRepos = append(stageRepos, &StageRepo{
+		stageRepos = append(stageRepos, &model.StageRepo{
 			URL:         repo + "@" + hash,
 			Stars:       stars,
 			OpenIssues:  openIssues,
 			Updated:     updated,
 			Size:        size,
 			InstallSize: installSize,
+			Images:      images,
+			Readme:      readme,
+			License:     license,
+			Archived:    health.Archived,
 			Package:     pkg,
 		})
 		logger.Infof("updated repo [%s]", repo)
@@ -173,28 +167,25 @@ func performStage(typ string) {
 	p.Release()
 
 	sort.SliceStable(stageRepos, func(i, j int) bool {
-		return stageRepos[i].(*StageRepo).Updated > stageRepos[j].(*StageRepo).Updated
+		return stageRepos[i].Updated > stageRepos[j].Updated
 	})
+	applyTrending(stageRepos)
 
-	staged := map[string]interface{}{
-		"repos": stageRepos,
-	}
-
-	data, err := gulu.JSON.MarshalIndentJSON(staged, "", "  ")
-	if nil != err {
-		logger.Fatalf("marshal stage [%s.json] failed: %s", typ, err)
-	}
-
-	if err = os.WriteFile("stage/"+typ+".json", data, 0644); nil != err {
+	staged := &model.StageFile{Repos: stageRepos}
+	if err = model.SaveStageFile("stage/"+typ+".json", staged); nil != err {
 		logger.Fatalf("write stage [%s.json] failed: %s", typ, err)
 	}
 
 	logger.Infof("staged [%s]", typ)
+	return staged
 }
 
-// indexPackage 索引包，返回的 pkg 为 *Package / *PluginPackage / *ThemePackage 之一
-func indexPackage(repoURL, typ string) (ok bool, hash, published string, size, installSize int64, pkg interface{}) {
-	hash, published, packageZip, releaseOk := getRepoLatestRelease(repoURL)
+// indexPackage 索引包，返回的 pkg 为 *model.Package / *model.PluginPackage / *model.ThemePackage 之一，
+// images 为预览图、图标的变体，readme 为各 locale 解析后的 README 路径，license 为 SPDX 许可证标识符；
+// pinnedTag 为列表中 pinned 标注的 tag，不为空时索引该 tag 的发布而不是最新发布；
+// old 为该仓库的旧 stage 数据，发布提交未变时沿用其中的图片变体及许可证，不再重新下载、识别
+func indexPackage(repoURL, typ, pinnedTag string, old *model.StageRepo) (ok bool, hash, published string, size, installSize int64, pkg model.Manifest, images *model.Images, readme model.LocaleStrings, license string) {
+	hash, published, packageZip, releaseOk := getRepoLatestRelease(repoURL, pinnedTag)
 	if !releaseOk {
 		logger.Warnf("get [%s] latest release failed", repoURL)
 		return
@@ -246,126 +237,109 @@ func indexPackage(repoURL, typ string) (ok bool, hash, published string, size, i
 	}
 
 	// 先获取包配置，以便根据配置上传对应的 README 文件
-	var basePkg *Package
-	pkg, basePkg = getPackage(repoURL, hash, typ)
-	if nil == pkg || nil == basePkg {
+	pkg = getPackage(repoURL, hash, typ)
+	if nil == pkg {
 		logger.Warnf("get package [%s] failed", repoURL)
 		return
 	}
-
-	// 收集需要上传的 README 文件列表（根据包配置中的 readme 字段）
-	readmeFiles := make(map[string]bool)
-	if nil != basePkg.Readme {
-		for _, readmePath := range basePkg.Readme {
-			if normalized, ok := normalizeReadmePath(readmePath); ok {
-				readmeFiles["/"+normalized] = true
-			}
-		}
+	basePkg := pkg.Base()
+	checkPackageContent(repoURL, typ, data, pkg)
+	unchanged := nil != old && hash == old.Hash()
+	if unchanged {
+		// 未识别出许可证的包已在该提交首次索引时记入运行报告，不再重复报告
+		license = old.License
+	} else {
+		license = detectLicense(repoURL, hash, data)
 	}
-	// 如果没有配置 readme 字段或所有字段都为空，则上传默认的 README 文件（向后兼容）
-	if 0 == len(readmeFiles) {
-		readmeFiles["/README_zh_CN.md"] = true
-		readmeFiles["/README_en_US.md"] = true
+
+	// 收集需要上传的 README 文件列表（根据包配置中的 readme 字段，未配置时上传约定的多语言 README 以向后兼容）
+	readmeFiles := map[string]bool{}
+	declared, _ := basePkg.DeclaredReadmes()
+	for _, readmePath := range declared {
+		readmeFiles[readmePath] = true
 	}
 	// 无论是否收集到 README.md 文件，都需要上传
-	readmeFiles["/README.md"] = true
+	readmeFiles[model.ReadmeFileName] = true
 
 	// 并发上传文件
 	wg := &sync.WaitGroup{}
-	wg.Add(3 + len(readmeFiles))
-	// 上传 README 文件
+	wg.Add(1 + len(readmeFiles))
+	// 上传 README 文件，其中引用的相对路径图片镜像到 OSS，并记录各 README 是否存在
+	assets := newReadmeAssets(repoURL, hash)
+	readmeExists := map[string]bool{}
+	readmeLock := sync.Mutex{}
 	for readmeFile := range readmeFiles {
-		go indexPackageFile(repoURL, hash, readmeFile, 0, 0, wg)
-	}
-	// 上传其他固定文件
-	go indexPackageFile(repoURL, hash, "/preview.png", 0, 0, wg)
-	go indexPackageFile(repoURL, hash, "/icon.png", 0, 0, wg)
-	go indexPackageFile(repoURL, hash, "/"+strings.TrimSuffix(typ, "s")+".json", size, installSize, wg)
+		go func(readmeFile string) {
+			defer wg.Done()
+			exists := indexPackageFile(repoURL, hash, "/"+readmeFile, 0, 0, assets)
+			readmeLock.Lock()
+			readmeExists[readmeFile] = exists
+			readmeLock.Unlock()
+		}(readmeFile)
+	}
+	// 上传其他固定文件，预览图与图标同时生成缩略图及 WebP 变体
+	if unchanged && nil != old.Images {
+		// 发布提交未变时图片变体已在 OSS 上，沿用旧数据
+		images = old.Images
+	} else {
+		images = &model.Images{}
+		wg.Add(2)
+		go indexPackageImage(repoURL, hash, "/preview.png", previewThumbnailBoxes, &images.Preview, wg)
+		go indexPackageImage(repoURL, hash, "/icon.png", nil, &images.Icon, wg)
+	}
+	go func() {
+		defer wg.Done()
+		indexPackageFile(repoURL, hash, "/"+model.ManifestName(typ)+".json", size, installSize, nil)
+	}()
 	wg.Wait()
+
+	readme = resolveReadme(repoURL, basePkg, readmeExists)
+	if 0 == len(images.Preview) && 0 == len(images.Icon) {
+		images = nil
+	}
 	ok = true
 	return
 }
 
-// getPackage 获取 release 对应提交中的 *.json 配置文件，按 typ 解析为 Package / PluginPackage / ThemePackage，并返回用于 Readme 等的 *Package
-func getPackage(ownerRepo, hash, typ string) (pkgVal interface{}, basePkg *Package) {
-	name := strings.TrimSuffix(typ, "s")
-	u := "https://raw.githubusercontent.com/" + ownerRepo + "/" + hash + "/" + name + ".json"
+// getPackage 获取 release 对应提交中的 *.json 配置文件，按 typ 解析为 *model.Package / *model.PluginPackage / *model.ThemePackage
+func getPackage(ownerRepo, hash, typ string) (pkg model.Manifest) {
+	u := "https://raw.githubusercontent.com/" + ownerRepo + "/" + hash + "/" + model.ManifestName(typ) + ".json"
 	resp, data, errs := gorequest.New().Get(u).
 		Set("User-Agent", util.UserAgent).
 		Retry(1, 3*time.Second).Timeout(30 * time.Second).EndBytes()
 	if nil != errs {
 		logger.Errorf("get [%s] failed: %s", u, errs)
-		return nil, nil
+		return nil
 	}
 	if 200 != resp.StatusCode {
-		return nil, nil
+		return nil
 	}
 
-	switch typ {
-	case "plugins":
-		p := &PluginPackage{Package: &Package{}}
-		if err := gulu.JSON.UnmarshalJSON(data, p); nil != err {
-			logger.Errorf("unmarshal [%s] failed: %s", u, err)
-			return nil, nil
-		}
-		sanitizePackage(p.Package)
-		return p, p.Package
-	case "themes":
-		p := &ThemePackage{Package: &Package{}}
-		if err := gulu.JSON.UnmarshalJSON(data, p); nil != err {
-			logger.Errorf("unmarshal [%s] failed: %s", u, err)
-			return nil, nil
-		}
-		sanitizePackage(p.Package)
-		return p, p.Package
-	default:
-		ret := &Package{}
-		if err := gulu.JSON.UnmarshalJSON(data, ret); nil != err {
-			logger.Errorf("unmarshal [%s] failed: %s", u, err)
-			return nil, nil
-		}
-		sanitizePackage(ret)
-		return ret, ret
+	pkg, err := model.ParseManifest(data, typ)
+	if nil != err {
+		logger.Errorf("unmarshal [%s] failed: %s", u, err)
+		return nil
 	}
+	sanitizePackage(ownerRepo, pkg.Base())
+	sanitizeEnums(ownerRepo, pkg)
+	return pkg
 }
 
-// normalizeReadmePath 规范化并校验 readme 路径，防止路径穿越；返回规范化后的相对路径（无前导斜杠）及是否合法
-func normalizeReadmePath(readmePath string) (string, bool) {
-	readmePath = strings.TrimSpace(readmePath)
-	if readmePath == "" {
-		return "", false
-	}
-	// 去掉前导斜杠/反斜杠，视为相对路径
-	readmePath = strings.TrimLeft(readmePath, "/\\")
-	// 统一为正向斜杠后交给 filepath 做跨平台清理
-	cleaned := filepath.Clean(filepath.FromSlash(readmePath))
-	normalized := filepath.ToSlash(cleaned)
-	// 拒绝含 .. 的路径，防止路径穿越
-	if strings.Contains(normalized, "..") {
-		return "", false
-	}
-	return normalized, true
-}
-
-// indexPackageFile 索引文件
-func indexPackageFile(ownerRepo, hash, filePath string, size, installSize int64, wg *sync.WaitGroup) bool {
-	defer wg.Done()
-
+// indexPackageFile 索引文件，assets 不为空时重写 Markdown 中的相对链接并上传渲染后的 HTML；文件不存在或上传失败时返回 false
+func indexPackageFile(ownerRepo, hash, filePath string, size, installSize int64, assets *readmeAssets) bool {
 	u := "https://raw.githubusercontent.com/" + ownerRepo + "/" + hash + filePath
-	resp, data, errs := gorequest.New().Get(u).
-		Set("User-Agent", util.UserAgent).
-		Retry(1, 3*time.Second).Timeout(30 * time.Second).EndBytes()
-	if nil != errs {
-		logger.Errorf("get [%s] failed: %s", u, errs)
-		return false
-	}
-	if 200 != resp.StatusCode {
+	data, ok := getPackageFile(u)
+	if !ok {
 		return false
 	}
 
 	var contentType string
 	if strings.HasSuffix(filePath, ".md") {
 		contentType = "text/markdown"
+		if nil != assets {
+			data = assets.rewriteReadme(filePath, data)
+			indexReadmeHTML(ownerRepo, hash, filePath, data)
+		}
 	} else if strings.HasSuffix(filePath, ".json") {
 		contentType = "application/json"
 		// 统计包大小
@@ -393,38 +367,71 @@ func indexPackageFile(ownerRepo, hash, filePath string, size, installSize int64,
 	return true
 }
 
-func repoStats(repoURL string) (stars, openIssues int, ok bool) {
-	result := map[string]interface{}{}
+// getPackageFile 获取 release 对应提交中的文件，文件不存在时返回 false
+func getPackageFile(u string) (data []byte, ok bool) {
+	resp, data, errs := gorequest.New().Get(u).
+		Set("User-Agent", util.UserAgent).
+		Retry(1, 3*time.Second).Timeout(30 * time.Second).EndBytes()
+	if nil != errs {
+		logger.Errorf("get [%s] failed: %s", u, errs)
+		return
+	}
+	if 200 != resp.StatusCode {
+		return
+	}
+	ok = true
+	return
+}
+
+// repoStats 获取仓库的星标数、未关闭的 issue 数、规范名称及仓库状态，仓库改名或转移后 fullName 为新的 owner/repo；
+// 接口表明仓库已不可访问（不存在、转为私有、被禁用或屏蔽）时 unavailable 为原因
+func repoStats(repoURL string) (stars, openIssues int, fullName string, health *model.RepoHealth, unavailable string, ok bool) {
+	result := &struct {
+		FullName   string `json:"full_name"`
+		Stars      int    `json:"stargazers_count"`
+		OpenIssues int    `json:"open_issues_count"`
+		model.RepoHealth
+	}{}
 	request := gorequest.New().TLSClientConfig(&tls.Config{InsecureSkipVerify: true})
 	pat := os.Getenv("PAT")
 	u := "https://api.github.com/repos/" + repoURL
 	resp, _, errs := request.Get(u).
 		Set("Authorization", "Token "+pat).
 		Set("User-Agent", util.UserAgent).Timeout(30*time.Second).
-		Retry(1, 3*time.Second).EndStruct(&result)
+		Retry(1, 3*time.Second).EndStruct(result)
 	if nil != errs {
 		logger.Warnf("get [%s] failed: %s", u, errs)
 		return
 	}
 	if 200 != resp.StatusCode {
+		// 触发速率限制时同样返回 403，此时只是暂时失败
+		if "0" != resp.Header.Get("X-RateLimit-Remaining") && "" == resp.Header.Get("Retry-After") {
+			unavailable = model.RepoUnavailableReason(resp.StatusCode)
+		}
 		logger.Warnf("get [%s] failed: %d", u, resp.StatusCode)
 		return
 	}
 
 	//logger.Infof("X-Ratelimit-Remaining=%s]", resp.Header.Get("X-Ratelimit-Remaining"))
-	stars = int(result["stargazers_count"].(float64))
-	openIssues = int(result["open_issues_count"].(float64))
+	stars = result.Stars
+	openIssues = result.OpenIssues
+	fullName = result.FullName
+	health = &result.RepoHealth
 	ok = true
 	return
 }
 
-// getRepoLatestRelease 获取仓库最新发布的版本
-func getRepoLatestRelease(repoURL string) (hash, published, packageZip string, ok bool) {
+// getRepoLatestRelease 获取仓库最新发布的版本，pinnedTag 不为空时获取该 tag 对应的发布
+func getRepoLatestRelease(repoURL, pinnedTag string) (hash, published, packageZip string, ok bool) {
 	result := map[string]interface{}{}
 	request := gorequest.New().TLSClientConfig(&tls.Config{InsecureSkipVerify: true})
 	pat := os.Getenv("PAT")
 	// REF https://docs.github.com/en/rest/releases/releases#get-the-latest-release
 	u := "https://api.github.com/repos/" + repoURL + "/releases/latest"
+	if "" != pinnedTag {
+		// REF https://docs.github.com/en/rest/releases/releases#get-a-release-by-tag-name
+		u = "https://api.github.com/repos/" + repoURL + "/releases/tags/" + url.PathEscape(pinnedTag)
+	}
 	resp, _, errs := request.Get(u).
 		Set("Authorization", "Token "+pat).
 		Set("User-Agent", util.UserAgent).Timeout(30*time.Second).
@@ -508,71 +515,3 @@ func getRepoLatestRelease(repoURL string) (hash, published, packageZip string, o
 	ok = true
 	return
 }
-
-// sanitizePackage 对 Package 中部分字段消毒
-func sanitizePackage(pkg *Package) {
-	// REF: https://pkg.go.dev/github.com/microcosm-cc/bluemonday#Policy.Sanitize
-	pkg.Name = sterilizer.Sanitize(pkg.Name)
-	pkg.Author = sterilizer.Sanitize(pkg.Author)
-
-	if nil != pkg.DisplayName {
-		for k, v := range pkg.DisplayName {
-			pkg.DisplayName[k] = sterilizer.Sanitize(v)
-		}
-	}
-
-	if nil != pkg.Description {
-		for k, v := range pkg.Description {
-			pkg.Description[k] = sterilizer.Sanitize(v)
-		}
-	}
-}
-
-// LocaleStrings 表示按 locale 键（如 default、zh_CN、en_US）组织的多语言字符串
-type LocaleStrings map[string]string
-
-type Funding struct {
-	OpenCollective string   `json:"openCollective"`
-	Patreon        string   `json:"patreon"`
-	GitHub         string   `json:"github"`
-	Custom         []string `json:"custom"`
-}
-
-type Package struct {
-	Name          string        `json:"name"`
-	Author        string        `json:"author"`
-	URL           string        `json:"url"`
-	Version       string        `json:"version"`
-	MinAppVersion string        `json:"minAppVersion"`
-	DisplayName   LocaleStrings `json:"displayName"`
-	Description   LocaleStrings `json:"description"`
-	Readme        LocaleStrings `json:"readme"`
-	Funding       *Funding      `json:"funding"`
-	Keywords      []string      `json:"keywords"`
-}
-
-// PluginPackage 插件的 package
-type PluginPackage struct {
-	*Package
-	Backends          []string `json:"backends"`
-	Frontends         []string `json:"frontends"`
-	DisabledInPublish bool     `json:"disabledInPublish"`
-}
-
-// ThemePackage 主题的 package
-type ThemePackage struct {
-	*Package
-	Modes []string `json:"modes"`
-}
-
-type StageRepo struct {
-	URL         string `json:"url"`
-	Updated     string `json:"updated"`
-	Stars       int    `json:"stars"`
-	OpenIssues  int    `json:"openIssues"`
-	Size        int64  `json:"size"`
-	InstallSize int64  `json:"installSize"`
-
-	// Package 为 *Package（模板/图标/挂件）、*PluginPackage（插件）或 *ThemePackage（主题）
-	Package interface{} `json:"package"`
-}
@@ -0,0 +1,370 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/siyuan-note/bazaar/actions/model"
+	"github.com/siyuan-note/bazaar/actions/util"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+// README 引用图片的镜像上限
+const (
+	maxReadmeImageSize   = 8 << 20  // 单张图片大小上限
+	maxReadmeImagesSize  = 32 << 20 // 每个包镜像图片的总大小上限
+	maxReadmeImagesCount = 64       // 每个包镜像图片的数量上限
+)
+
+// readmeImagesDir 镜像图片在 package/<owner>/<repo>@<hash>/ 下的目录，与包文件及生成的图片变体分开，避免 key 冲突
+const readmeImagesDir = "readme-images"
+
+// readmeImageExts 镜像到 OSS 的图片扩展名。SVG 可以包含脚本，不镜像到集市 CDN 域名下，指向该提交中文件的 raw 链接
+var readmeImageExts = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".webp": true, ".bmp": true, ".ico": true, ".avif": true,
+}
+
+var (
+	// readmeMarkdown 保留原始 HTML，渲染结果统一由 readmePolicy 消毒
+	readmeMarkdown    = goldmark.New(goldmark.WithExtensions(extension.GFM), goldmark.WithRendererOptions(html.WithUnsafe()))
+	readmePolicy      = newReadmePolicy()
+	htmlAttrURLRegexp = regexp.MustCompile(`(?i)\b(?:src|href)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+)
+
+// newReadmePolicy 在 UGC 策略基础上保留代码块语言及任务列表复选框，外链以新窗口打开且不带 referrer
+func newReadmePolicy() *bluemonday.Policy {
+	ret := bluemonday.UGCPolicy()
+	ret.RequireNoReferrerOnLinks(true)
+	ret.AddTargetBlankToFullyQualifiedLinks(true)
+	ret.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	ret.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	ret.AllowAttrs("checked", "disabled").OnElements("input")
+	return ret
+}
+
+// readmeAssets 一个包所有 README 共享的图片镜像状态，避免同一图片重复下载，并统计总量
+type readmeAssets struct {
+	ownerRepo, hash string
+
+	lock     sync.Mutex
+	mirrored map[string]*mirroredImage // 仓库内路径 -> 镜像结果
+	count    int
+	size     int64
+}
+
+// mirroredImage 一张图片的镜像结果，同一图片只镜像一次
+type mirroredImage struct {
+	once sync.Once
+	url  string // 重写后的链接
+}
+
+func newReadmeAssets(ownerRepo, hash string) *readmeAssets {
+	return &readmeAssets{ownerRepo: ownerRepo, hash: hash, mirrored: map[string]*mirroredImage{}}
+}
+
+// rewriteReadme 将 README 中的相对链接重写为绝对链接：图片镜像到 package/<owner>/<repo>@<hash>/readme-images/ 下并指向 CDN，SVG 指向 raw 链接，
+// 其他文件指向 GitHub 上该发布提交中的文件；readmePath 为 README 在仓库内的路径，用于解析相对路径
+func (assets *readmeAssets) rewriteReadme(readmePath string, data []byte) []byte {
+	baseDir := path.Dir(strings.TrimPrefix(readmePath, "/"))
+
+	dests, code := readmeDestinations(data)
+	rewrites := map[string]string{}
+	for _, dest := range dests {
+		if _, ok := rewrites[dest]; ok {
+			continue
+		}
+		if rewritten := assets.rewriteDestination(baseDir, dest); "" != rewritten {
+			rewrites[dest] = rewritten
+		}
+	}
+	if 0 == len(rewrites) {
+		return data
+	}
+
+	// 只替换链接语法中的目标，即 (dest、<dest>、: dest 及 HTML 属性中的 "dest"、'dest'，跳过代码块及行内代码中的内容。
+	// 较长的目标优先匹配，避免一个目标是另一个目标前缀时被截断替换
+	quoted := make([]string, 0, len(rewrites))
+	for dest := range rewrites {
+		quoted = append(quoted, regexp.QuoteMeta(dest))
+	}
+	sort.Slice(quoted, func(i, j int) bool { return len(quoted[i]) > len(quoted[j]) })
+	re := regexp.MustCompile(`([(<"']|\]:[ \t]*)(` + strings.Join(quoted, "|") + `)[)>"'\s]`)
+
+	buf := &bytes.Buffer{}
+	last := 0
+	for _, m := range re.FindAllSubmatchIndex(data, -1) {
+		start, end := m[4], m[5]
+		if inSegments(code, start) {
+			continue
+		}
+		buf.Write(data[last:start])
+		buf.WriteString(rewrites[string(data[start:end])])
+		last = end
+	}
+	buf.Write(data[last:])
+	return buf.Bytes()
+}
+
+// inSegments 判断偏移 offset 是否落在某个片段内
+func inSegments(segments []text.Segment, offset int) bool {
+	for _, segment := range segments {
+		if segment.Start <= offset && offset < segment.Stop {
+			return true
+		}
+	}
+	return false
+}
+
+// indexReadmeHTML 将 README 渲染为消毒后的 HTML，上传到 .md 同目录下的同名 .html 文件
+func indexReadmeHTML(ownerRepo, hash, filePath string, data []byte) {
+	rendered, err := renderReadme(data)
+	if nil != err {
+		logger.Errorf("render readme [%s%s] failed: %s", ownerRepo, filePath, err)
+		return
+	}
+	key := "package/" + ownerRepo + "@" + hash + strings.TrimSuffix(filePath, path.Ext(filePath)) + ".html"
+	if err = util.UploadOSS(key, "text/html; charset=utf-8", rendered); nil != err {
+		logger.Errorf("upload package file [%s] failed: %s", key, err)
+	}
+}
+
+// renderReadme 将 README 渲染为 HTML 并消毒
+func renderReadme(data []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := readmeMarkdown.Convert(data, buf); nil != err {
+		return nil, err
+	}
+	return readmePolicy.SanitizeBytes(buf.Bytes()), nil
+}
+
+// readmeDestinations 解析 Markdown，返回链接、图片及内联 HTML 中 src/href 属性的目标，以及代码块和行内代码所在的片段
+func readmeDestinations(data []byte) (ret []string, code []text.Segment) {
+	doc := readmeMarkdown.Parser().Parse(text.NewReader(data))
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.CodeSpan:
+			for child := node.FirstChild(); nil != child; child = child.NextSibling() {
+				if t, ok := child.(*ast.Text); ok {
+					code = append(code, t.Segment)
+				}
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			for i := 0; i < node.Lines().Len(); i++ {
+				code = append(code, node.Lines().At(i))
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.Link:
+			ret = append(ret, string(node.Destination))
+		case *ast.Image:
+			ret = append(ret, string(node.Destination))
+		case *ast.RawHTML:
+			for i := 0; i < node.Segments.Len(); i++ {
+				segment := node.Segments.At(i)
+				ret = append(ret, htmlAttrURLs(segment.Value(data))...)
+			}
+		case *ast.HTMLBlock:
+			for i := 0; i < node.Lines().Len(); i++ {
+				line := node.Lines().At(i)
+				ret = append(ret, htmlAttrURLs(line.Value(data))...)
+			}
+			if node.HasClosure() {
+				ret = append(ret, htmlAttrURLs(node.ClosureLine.Value(data))...)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return
+}
+
+func htmlAttrURLs(html []byte) (ret []string) {
+	for _, m := range htmlAttrURLRegexp.FindAllSubmatch(html, -1) {
+		if 0 < len(m[1]) {
+			ret = append(ret, string(m[1]))
+		} else {
+			ret = append(ret, string(m[2]))
+		}
+	}
+	return
+}
+
+// rewriteDestination 返回相对链接 dest 重写后的绝对链接，无需重写（绝对链接、锚点）或非法（越出仓库）时返回空字符串
+func (assets *readmeAssets) rewriteDestination(baseDir, dest string) string {
+	dest = strings.TrimSpace(dest)
+	if "" == dest || strings.HasPrefix(dest, "#") || strings.HasPrefix(dest, "//") {
+		return ""
+	}
+	u, err := url.Parse(dest)
+	if nil != err || "" != u.Scheme || "" != u.Host || "" == u.Path {
+		return ""
+	}
+
+	// GitHub 上以 / 开头的链接相对于仓库根目录
+	repoPath := u.Path
+	if !strings.HasPrefix(repoPath, "/") {
+		repoPath = path.Join(baseDir, repoPath)
+	}
+	repoPath = strings.TrimPrefix(path.Clean("/"+repoPath), "/")
+	if "" == repoPath || strings.HasPrefix(repoPath, "..") {
+		return ""
+	}
+
+	suffix := ""
+	if "" != u.RawQuery {
+		suffix += "?" + u.RawQuery
+	}
+	if "" != u.Fragment {
+		suffix += "#" + u.EscapedFragment()
+	}
+
+	ext := strings.ToLower(path.Ext(repoPath))
+	if readmeImageExts[ext] {
+		return assets.mirrorImage(repoPath)
+	}
+	if ".svg" == ext {
+		return assets.rawURL(repoPath)
+	}
+	return "https://github.com/" + assets.ownerRepo + "/blob/" + assets.hash + "/" + escapePath(repoPath) + suffix
+}
+
+// mirrorImage 返回图片 repoPath 重写后的链接，首次调用时镜像该图片
+func (assets *readmeAssets) mirrorImage(repoPath string) string {
+	assets.lock.Lock()
+	image := assets.mirrored[repoPath]
+	if nil == image {
+		image = &mirroredImage{}
+		assets.mirrored[repoPath] = image
+	}
+	assets.lock.Unlock()
+
+	image.once.Do(func() {
+		image.url = assets.uploadImage(repoPath)
+	})
+	return image.url
+}
+
+// uploadImage 下载仓库中的图片并上传到 OSS，返回 CDN 链接；超出数量或大小上限、下载失败时返回该提交中文件的 raw 链接
+func (assets *readmeAssets) uploadImage(repoPath string) string {
+	rawURL := assets.rawURL(repoPath)
+	assets.lock.Lock()
+	if maxReadmeImagesCount <= assets.count {
+		assets.lock.Unlock()
+		stageReport.add("README images not mirrored", "[%s](https://github.com/%s): `%s` exceeds the limit of %d images", assets.ownerRepo, assets.ownerRepo, repoPath, maxReadmeImagesCount)
+		return rawURL
+	}
+	assets.count++
+	assets.lock.Unlock()
+
+	data, err := downloadLimited(rawURL, maxReadmeImageSize)
+	if nil != err {
+		stageReport.add("README images not mirrored", "[%s](https://github.com/%s): `%s` %s", assets.ownerRepo, assets.ownerRepo, repoPath, err)
+		return rawURL
+	}
+
+	assets.lock.Lock()
+	if maxReadmeImagesSize < assets.size+int64(len(data)) {
+		assets.lock.Unlock()
+		stageReport.add("README images not mirrored", "[%s](https://github.com/%s): `%s` exceeds the total size limit of %d MB", assets.ownerRepo, assets.ownerRepo, repoPath, maxReadmeImagesSize>>20)
+		return rawURL
+	}
+	assets.size += int64(len(data))
+	assets.lock.Unlock()
+
+	contentType := mime.TypeByExtension(strings.ToLower(path.Ext(repoPath)))
+	if "" == contentType {
+		contentType = http.DetectContentType(data)
+	}
+	key := "package/" + assets.ownerRepo + "@" + assets.hash + "/" + readmeImagesDir + "/" + repoPath
+	if err = util.UploadOSS(key, contentType, data); nil != err {
+		logger.Errorf("upload readme image [%s] failed: %s", key, err)
+		return rawURL
+	}
+	return util.OSSServer + "/package/" + assets.ownerRepo + "@" + assets.hash + "/" + readmeImagesDir + "/" + escapePath(repoPath)
+}
+
+// rawURL 返回该提交中文件 repoPath 的 raw 链接
+func (assets *readmeAssets) rawURL(repoPath string) string {
+	return "https://raw.githubusercontent.com/" + assets.ownerRepo + "/" + assets.hash + "/" + escapePath(repoPath)
+}
+
+// downloadLimited 下载 u，响应体超过 limit 字节时返回错误
+func downloadLimited(u string, limit int64) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if nil != err {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", util.UserAgent)
+	resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+	if nil != err {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if 200 != resp.StatusCode {
+		return nil, fmt.Errorf("download failed: %d", resp.StatusCode)
+	}
+	if limit < resp.ContentLength {
+		return nil, fmt.Errorf("exceeds the size limit of %d MB", limit>>20)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if nil != err {
+		return nil, err
+	}
+	if limit < int64(len(data)) {
+		return nil, fmt.Errorf("exceeds the size limit of %d MB", limit>>20)
+	}
+	return data, nil
+}
+
+// escapePath 逐段转义仓库内路径
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// resolveReadme 按 locale -> default -> README.md 的顺序解析各 locale 实际使用的 README 路径，清单中声明了但不存在的 README 记入运行报告
+func resolveReadme(ownerRepo string, pkg *model.Package, readmeExists map[string]bool) model.LocaleStrings {
+	resolved, missing := pkg.ResolveReadme(model.Locales, func(readmePath string) bool {
+		return readmeExists[readmePath]
+	})
+	for locale, readmePath := range missing {
+		stageReport.add("Missing README files", "[%s](https://github.com/%s): `readme.%s` `%s` does not exist", ownerRepo, ownerRepo, locale, readmePath)
+	}
+	if _, ok := resolved["default"]; !ok {
+		stageReport.add("Missing README files", "[%s](https://github.com/%s): no README is available, `%s` does not exist", ownerRepo, ownerRepo, model.ReadmeFileName)
+	}
+	if 0 == len(resolved) {
+		return nil
+	}
+	return resolved
+}
@@ -0,0 +1,79 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// stageReport 本次 stage 运行报告，运行结束时写入 STAGE_REPORT_OUTPUT 指定的文件，未指定时写入 GitHub Actions 运行摘要
+var stageReport = &runReport{sections: map[string][]string{}}
+
+// runReport 按章节收集的运行报告条目
+type runReport struct {
+	lock     sync.Mutex
+	order    []string            // 章节按首次出现的顺序输出
+	sections map[string][]string // 章节 -> 条目（Markdown）
+}
+
+// add 向章节追加一条 Markdown 记录
+func (r *runReport) add(section, format string, args ...interface{}) {
+	item := fmt.Sprintf(format, args...)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.sections[section]; !ok {
+		r.order = append(r.order, section)
+	}
+	r.sections[section] = append(r.sections[section], item)
+}
+
+// write 输出 Markdown 格式的运行报告，章节内条目排序以避免并发导致顺序不稳定
+func (r *runReport) write() {
+	output := os.Getenv("STAGE_REPORT_OUTPUT")
+	if "" == output {
+		output = os.Getenv("GITHUB_STEP_SUMMARY")
+	}
+	if "" == output {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	buf := &strings.Builder{}
+	buf.WriteString("## Stage Report\n\n")
+	if 0 == len(r.order) {
+		buf.WriteString("Nothing to report.\n")
+	}
+	for _, section := range r.order {
+		items := r.sections[section]
+		sort.Strings(items)
+		buf.WriteString(fmt.Sprintf("### %s (%d)\n\n", section, len(items)))
+		for _, item := range items {
+			buf.WriteString("- " + item + "\n")
+		}
+		buf.WriteString("\n")
+	}
+
+	f, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if nil != err {
+		logger.Errorf("open stage report [%s] failed: %s", output, err)
+		return
+	}
+	defer f.Close()
+	if _, err = f.WriteString(buf.String()); nil != err {
+		logger.Errorf("write stage report [%s] failed: %s", output, err)
+	}
+}
@@ -0,0 +1,298 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package main
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/siyuan-note/bazaar/actions/model"
+)
+
+// 包配置字段的长度上限（按字符计）
+const (
+	maxNameLen        = 128
+	maxDisplayNameLen = 128
+	maxDescriptionLen = 1024
+	maxURLLen         = 2048
+	maxReadmePathLen  = 256
+	maxVersionLen     = 64
+	maxKeywordLen     = 64
+	maxKeywords       = 32
+	maxFundingCustom  = 8
+)
+
+// sanitizedFieldSection 运行报告中记录字段修改的章节
+const sanitizedFieldSection = "Sanitized manifest fields"
+
+var (
+	localeKeyRegexp      = regexp.MustCompile(`^[A-Za-z0-9_-]{1,16}$`)
+	fundingAccountRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,99}$`)
+	versionPrefixRegexp  = regexp.MustCompile(`\d+\.\d+(?:\.\d+)?`)
+)
+
+// fieldReporter 记录对某字段的一处修改
+type fieldReporter func(field, format string, args ...interface{})
+
+// sanitizePackage 对 Package 中所有用户可控字段消毒：去除控制字符、限制长度、仅允许 http(s) 链接、校验版本号，
+// 无法修复的值被丢弃，每处修改都记入运行报告
+func sanitizePackage(ownerRepo string, pkg *model.Package) {
+	var report fieldReporter = func(field, format string, args ...interface{}) {
+		args = append([]interface{}{ownerRepo, ownerRepo, field}, args...)
+		stageReport.add(sanitizedFieldSection, "[%s](https://github.com/%s): `%s` "+format, args...)
+	}
+
+	// REF: https://pkg.go.dev/github.com/microcosm-cc/bluemonday#Policy.Sanitize
+	pkg.Name = sanitizeText(pkg.Name, maxNameLen, "name", report)
+	pkg.Author = sanitizeText(pkg.Author, maxNameLen, "author", report)
+	pkg.DisplayName = sanitizeLocaleStrings(pkg.DisplayName, "displayName", report, func(v, field string) string {
+		return sanitizeText(v, maxDisplayNameLen, field, report)
+	})
+	pkg.Description = sanitizeLocaleStrings(pkg.Description, "description", report, func(v, field string) string {
+		return sanitizeText(v, maxDescriptionLen, field, report)
+	})
+	pkg.Readme = sanitizeLocaleStrings(pkg.Readme, "readme", report, func(v, field string) string {
+		return sanitizeReadmePath(v, field, report)
+	})
+
+	pkg.URL = sanitizeURL(pkg.URL, "url", report)
+	pkg.Version = sanitizeVersion(pkg.Version, true, "version", report)
+	pkg.MinAppVersion = sanitizeVersion(pkg.MinAppVersion, false, "minAppVersion", report)
+	pkg.MaxAppVersion = sanitizeVersion(pkg.MaxAppVersion, false, "maxAppVersion", report)
+	pkg.Keywords = sanitizeKeywords(pkg.Keywords, report)
+	sanitizeFunding(pkg.Funding, report)
+}
+
+// sanitizeEnums 移除插件 backends、frontends 及主题 modes 字段中不合法的取值，并记入运行报告
+func sanitizeEnums(ownerRepo string, manifest model.Manifest) {
+	for _, invalid := range model.ValidateEnums(manifest, true) {
+		values := make([]string, 0, len(invalid.Values))
+		for _, value := range invalid.Values {
+			values = append(values, truncateRunes(stripControl(value), 32))
+		}
+		stageReport.add(sanitizedFieldSection, "[%s](https://github.com/%s): `%s` dropped unknown values `%s`", ownerRepo, ownerRepo, invalid.Field, strings.Join(values, "`, `"))
+	}
+}
+
+// sanitizeText 去除控制字符并截断到 maxLen 个字符，再经 bluemonday 消毒。空白归一化及 HTML 实体转义不记入运行报告
+func sanitizeText(value string, maxLen int, field string, report fieldReporter) string {
+	ret := stripControl(value)
+	if hasControl(value) {
+		report(field, "control characters removed")
+	}
+	if truncated := truncateRunes(ret, maxLen); truncated != ret {
+		report(field, "truncated to %d characters", maxLen)
+		ret = truncated
+	}
+	if sanitized := sterilizer.Sanitize(ret); sanitized != ret {
+		if html.UnescapeString(sanitized) != html.UnescapeString(ret) {
+			report(field, "HTML sanitized")
+		}
+		ret = sanitized
+	}
+	return ret
+}
+
+// sanitizeLocaleStrings 丢弃非法的 locale 键后逐项消毒，消毒后为空的项被丢弃
+func sanitizeLocaleStrings(values model.LocaleStrings, field string, report fieldReporter, sanitize func(v, field string) string) model.LocaleStrings {
+	if nil == values {
+		return nil
+	}
+	for locale, v := range values {
+		if !localeKeyRegexp.MatchString(locale) {
+			report(field, "dropped invalid locale key `%s`", truncateRunes(stripControl(locale), 32))
+			delete(values, locale)
+			continue
+		}
+		if "" == v {
+			continue
+		}
+		if v = sanitize(v, field+"."+locale); "" == v {
+			delete(values, locale)
+			continue
+		}
+		values[locale] = v
+	}
+	return values
+}
+
+// sanitizeURL 仅保留带主机名的 http(s) 链接，其余值被丢弃
+func sanitizeURL(value, field string, report fieldReporter) string {
+	ret := strings.TrimSpace(stripControl(value))
+	if "" == ret {
+		return ""
+	}
+	if maxURLLen < len(ret) {
+		report(field, "dropped: longer than %d bytes", maxURLLen)
+		return ""
+	}
+	u, err := url.Parse(ret)
+	if nil != err || ("http" != strings.ToLower(u.Scheme) && "https" != strings.ToLower(u.Scheme)) || "" == u.Host {
+		report(field, "dropped: `%s` is not an http(s) URL", truncateRunes(ret, 64))
+		return ""
+	}
+	if ret != value {
+		report(field, "whitespace or control characters removed")
+	}
+	return ret
+}
+
+// sanitizeVersion 校验语义化版本，非法时尝试取出其中的版本号前缀，无法修复时丢弃；required 为 true 时丢弃会额外说明
+func sanitizeVersion(value string, required bool, field string, report fieldReporter) string {
+	ret := strings.TrimSpace(stripControl(value))
+	if "" == ret {
+		return ""
+	}
+	if maxVersionLen < len(ret) {
+		ret = truncateRunes(ret, maxVersionLen)
+	}
+	if _, ok := model.ParseVersion(ret); ok {
+		if ret != value {
+			report(field, "repaired `%s` → `%s`", truncateRunes(value, 64), ret)
+		}
+		return ret
+	}
+
+	if repaired := versionPrefixRegexp.FindString(ret); "" != repaired {
+		if _, ok := model.ParseVersion(repaired); ok {
+			report(field, "repaired `%s` → `%s`", ret, repaired)
+			return repaired
+		}
+	}
+	if required {
+		report(field, "dropped: `%s` is not a semantic version, package can not be compared for updates", ret)
+	} else {
+		report(field, "dropped: `%s` is not a semantic version", ret)
+	}
+	return ""
+}
+
+// sanitizeReadmePath 规范化 readme 路径，只允许仓库内的 Markdown 文件
+func sanitizeReadmePath(value, field string, report fieldReporter) string {
+	cleaned := stripControl(value)
+	if maxReadmePathLen < len(cleaned) {
+		report(field, "dropped: longer than %d bytes", maxReadmePathLen)
+		return ""
+	}
+	ret, ok := model.NormalizeReadmePath(cleaned)
+	if !ok || !strings.HasSuffix(strings.ToLower(ret), ".md") {
+		report(field, "dropped: `%s` is not a Markdown file inside the repository", truncateRunes(cleaned, 64))
+		return ""
+	}
+	if ret != value {
+		report(field, "normalized `%s` → `%s`", truncateRunes(value, 64), ret)
+	}
+	return ret
+}
+
+// sanitizeKeywords 消毒关键词，丢弃空值及超出数量上限的部分
+func sanitizeKeywords(keywords []string, report fieldReporter) (ret []string) {
+	for i, keyword := range keywords {
+		if maxKeywords <= len(ret) {
+			report("keywords", "dropped %d keywords beyond the limit of %d", len(keywords)-i, maxKeywords)
+			break
+		}
+		if keyword = strings.TrimSpace(sanitizeText(keyword, maxKeywordLen, "keywords", report)); "" == keyword {
+			continue
+		}
+		ret = append(ret, keyword)
+	}
+	return
+}
+
+// sanitizeFunding 消毒赞助配置，openCollective、patreon、github 为账号名或 http(s) 链接，custom 仅允许 http(s) 链接
+func sanitizeFunding(funding *model.Funding, report fieldReporter) {
+	if nil == funding {
+		return
+	}
+	account := func(value, field string) string {
+		ret := strings.TrimSpace(stripControl(value))
+		if "" == ret {
+			return ""
+		}
+		if strings.Contains(ret, ":") || strings.Contains(ret, "/") {
+			return sanitizeURL(value, field, report)
+		}
+		if !fundingAccountRegexp.MatchString(ret) {
+			report(field, "dropped: `%s` is not a valid account name", truncateRunes(ret, 64))
+			return ""
+		}
+		if ret != value {
+			report(field, "whitespace or control characters removed")
+		}
+		return ret
+	}
+	funding.OpenCollective = account(funding.OpenCollective, "funding.openCollective")
+	funding.Patreon = account(funding.Patreon, "funding.patreon")
+	funding.GitHub = account(funding.GitHub, "funding.github")
+
+	var custom []string
+	for _, link := range funding.Custom {
+		if link = sanitizeURL(link, "funding.custom", report); "" == link {
+			continue
+		}
+		if maxFundingCustom <= len(custom) {
+			report("funding.custom", "dropped links beyond the limit of %d", maxFundingCustom)
+			break
+		}
+		custom = append(custom, link)
+	}
+	funding.Custom = custom
+}
+
+// stripControl 将换行、回车、制表符替换为空格并合并连续空格，去除其他控制字符、双向文本控制符及 BOM，保留组合 emoji 所需的零宽连接符
+func stripControl(s string) string {
+	buf := &strings.Builder{}
+	space := false
+	for _, r := range s {
+		if '\n' == r || '\r' == r || '\t' == r {
+			r = ' '
+		}
+		if ' ' == r {
+			if !space {
+				buf.WriteRune(r)
+			}
+			space = true
+			continue
+		}
+		if unicode.IsControl(r) || unicode.Is(unicode.Bidi_Control, r) || '\uFEFF' == r {
+			continue
+		}
+		buf.WriteRune(r)
+		space = false
+	}
+	return buf.String()
+}
+
+// hasControl 判断是否包含会被 stripControl 去除的控制字符、双向文本控制符或 BOM，换行、回车、制表符不计
+func hasControl(s string) bool {
+	for _, r := range s {
+		if '\n' == r || '\r' == r || '\t' == r {
+			continue
+		}
+		if unicode.IsControl(r) || unicode.Is(unicode.Bidi_Control, r) || '\uFEFF' == r {
+			return true
+		}
+	}
+	return false
+}
+
+// truncateRunes 截断到最多 maxLen 个字符
+func truncateRunes(s string, maxLen int) string {
+	runes := []rune(s)
+	if maxLen >= len(runes) {
+		return s
+	}
+	return string(runes[:maxLen])
+}
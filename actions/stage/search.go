@@ -0,0 +1,115 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package main
+
+import (
+	"html"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/siyuan-note/bazaar/actions/model"
+)
+
+// stageSearchIndexes 为每个 locale 生成搜索索引 stage/search/<locale>.json
+func stageSearchIndexes(stageFiles map[string]*model.StageFile) {
+	logger.Infof("staging [%s]", model.SearchDir)
+
+	// 包 id 为在 packages 中的下标，按类型、owner/repo 排序，避免 stars/updated 变化导致 id 整体偏移
+	var packages []*model.SearchPackage
+	var manifests []*model.Package
+	for _, typ := range model.Types {
+		stageFile := stageFiles[typ]
+		if nil == stageFile {
+			continue
+		}
+		repos := make([]*model.StageRepo, 0, len(stageFile.Repos))
+		for _, repo := range stageFile.Repos {
+			if nil != repo.Package {
+				repos = append(repos, repo)
+			}
+		}
+		sort.SliceStable(repos, func(i, j int) bool {
+			return repos[i].RepoPath() < repos[j].RepoPath()
+		})
+		for _, repo := range repos {
+			pkg := repo.Package.Base()
+			packages = append(packages, &model.SearchPackage{Type: typ, Repo: repo.RepoPath(), Name: pkg.Name})
+			manifests = append(manifests, pkg)
+		}
+	}
+
+	searchDir := filepath.Join("stage", model.SearchDir)
+	if err := os.MkdirAll(searchDir, 0755); nil != err {
+		logger.Fatalf("mkdir [%s] failed: %s", searchDir, err)
+	}
+	for _, locale := range model.Locales {
+		index := buildSearchIndex(locale, packages, manifests)
+		if err := model.SaveSearchIndex(filepath.Join(searchDir, locale+".json"), index); nil != err {
+			logger.Fatalf("write search index [%s] failed: %s", locale, err)
+		}
+	}
+
+	logger.Infof("staged [%s]", model.SearchDir)
+}
+
+// buildSearchIndex 构建指定 locale 的倒排索引，同一包的同一词项在多个字段中出现时权重累加，同一字段内只计一次
+func buildSearchIndex(locale string, packages []*model.SearchPackage, manifests []*model.Package) *model.SearchIndex {
+	weights := map[string]map[int]int{}
+	addField := func(id int, field, text string) {
+		seen := map[string]bool{}
+		for _, term := range model.Tokenize(html.UnescapeString(text)) {
+			if seen[term] {
+				continue
+			}
+			seen[term] = true
+			if nil == weights[term] {
+				weights[term] = map[int]int{}
+			}
+			weights[term][id] += model.SearchFieldWeights[field]
+		}
+	}
+
+	for id, pkg := range manifests {
+		addField(id, model.SearchFieldName, pkg.Name)
+		displayName := pkg.DisplayName.LocalizedString(locale)
+		if "" == displayName {
+			displayName = pkg.Name
+		}
+		addField(id, model.SearchFieldDisplayName, displayName)
+		addField(id, model.SearchFieldKeywords, strings.Join(pkg.Keywords, " "))
+		addField(id, model.SearchFieldDescription, pkg.Description.LocalizedString(locale))
+	}
+
+	terms := make(map[string][]model.SearchPosting, len(weights))
+	for term, postings := range weights {
+		list := make([]model.SearchPosting, 0, len(postings))
+		for id, weight := range postings {
+			list = append(list, model.SearchPosting{id, weight})
+		}
+		sort.Slice(list, func(i, j int) bool {
+			if list[i][1] != list[j][1] {
+				return list[i][1] > list[j][1]
+			}
+			return list[i][0] < list[j][0]
+		})
+		terms[term] = list
+	}
+
+	return &model.SearchIndex{
+		SchemaVersion: model.SearchSchemaVersion,
+		Locale:        locale,
+		Fields:        model.SearchFieldWeights,
+		Packages:      packages,
+		Terms:         terms,
+	}
+}
@@ -0,0 +1,117 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package main
+
+import (
+	"crypto/tls"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/parnurzeal/gorequest"
+	"github.com/siyuan-note/bazaar/actions/model"
+	"github.com/siyuan-note/bazaar/actions/util"
+)
+
+var (
+	statsPath    = "stage/" + model.StatsFileName
+	statsNow     = time.Now().UTC()
+	statsHistory = &model.StatsHistory{}
+	statsToday   *model.StatsDay // 今日待追加的统计，今日已记录时为 nil（每天只在首次运行时采样）
+	statsLock    = sync.Mutex{}
+)
+
+// loadStats 加载统计时间序列
+func loadStats() {
+	history, err := model.LoadStatsHistory(statsPath)
+	if nil != err {
+		logger.Fatalf("load stats [%s] failed: %s", statsPath, err)
+	}
+	statsHistory = history
+
+	today := statsNow.Format(model.StatsDateLayout)
+	if !statsHistory.HasDate(today) {
+		statsToday = &model.StatsDay{Date: today, Repos: map[string]model.StatsPoint{}}
+	}
+}
+
+// sampleStats 采样包的今日统计，今日已记录时直接返回
+func sampleStats(repoURL string, stars int, size int64) {
+	statsLock.Lock()
+	needSample := nil != statsToday
+	statsLock.Unlock()
+	if !needSample {
+		return
+	}
+
+	downloads, ok := repoDownloads(repoURL)
+	if !ok {
+		return
+	}
+
+	statsLock.Lock()
+	statsToday.Repos[repoURL] = model.StatsPoint{int64(stars), downloads, size}
+	statsLock.Unlock()
+}
+
+// applyTrending 根据统计时间序列计算各包的热度
+func applyTrending(stageRepos []*model.StageRepo) {
+	for _, repo := range stageRepos {
+		repo.Trending = statsHistory.Trending(repo.RepoPath(), int64(repo.Stars), statsNow)
+	}
+}
+
+// saveStats 向统计文件追加今日的统计
+func saveStats() {
+	if nil == statsToday || 0 == len(statsToday.Repos) {
+		return
+	}
+
+	if err := model.AppendStatsDay(statsPath, statsToday); nil != err {
+		logger.Fatalf("append stats [%s] failed: %s", statsPath, err)
+	}
+	logger.Infof("recorded stats of [%d] repos for [%s]", len(statsToday.Repos), statsToday.Date)
+}
+
+// repoDownloads 统计仓库各发布版本（最近 100 个）中 package.zip 的下载次数之和
+func repoDownloads(repoURL string) (downloads int64, ok bool) {
+	var result []map[string]interface{}
+	request := gorequest.New().TLSClientConfig(&tls.Config{InsecureSkipVerify: true})
+	pat := os.Getenv("PAT")
+	// REF https://docs.github.com/en/rest/releases/releases#list-releases
+	u := "https://api.github.com/repos/" + repoURL + "/releases?per_page=100"
+	resp, _, errs := request.Get(u).
+		Set("Authorization", "Token "+pat).
+		Set("User-Agent", util.UserAgent).Timeout(30*time.Second).
+		Retry(1, 3*time.Second).EndStruct(&result)
+	if nil != errs {
+		logger.Warnf("get [%s] failed: %s", u, errs)
+		return
+	}
+	if 200 != resp.StatusCode {
+		logger.Warnf("get [%s] failed: %d", u, resp.StatusCode)
+		return
+	}
+
+	for _, release := range result {
+		assets, _ := release["assets"].([]interface{})
+		for _, asset := range assets {
+			asset, _ := asset.(map[string]interface{})
+			if name, _ := asset["name"].(string); "package.zip" != name {
+				continue
+			}
+			count, _ := asset["download_count"].(float64)
+			downloads += int64(count)
+		}
+	}
+	ok = true
+	return
+}
@@ -0,0 +1,86 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package main
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/siyuan-note/bazaar/actions/model"
+)
+
+// stageTags 按关键词分类表规范化所有包的关键词，生成 stage/tags.json，并在运行报告中列出被规范化及未知的关键词
+func stageTags(stageFiles map[string]*model.StageFile) {
+	logger.Infof("staging [%s]", model.TagsName)
+
+	taxonomy, err := model.LoadTaxonomy(model.KeywordsFileName)
+	if nil != err {
+		if !os.IsNotExist(err) {
+			logger.Fatalf("load keyword taxonomy [%s] failed: %s", model.KeywordsFileName, err)
+		}
+		taxonomy = model.Taxonomy{}
+	}
+
+	tags := map[string]*model.Tag{}
+	for _, typ := range model.Types {
+		stageFile := stageFiles[typ]
+		if nil == stageFile {
+			continue
+		}
+		for _, repo := range stageFile.Repos {
+			repoPath := repo.RepoPath()
+			if "" == repoPath || nil == repo.Package {
+				continue
+			}
+
+			result := taxonomy.Canonicalize(repo.Package.Base().Keywords)
+			for _, tag := range result.Tags {
+				if nil == tags[tag] {
+					_, known := taxonomy[tag]
+					tags[tag] = &model.Tag{Tag: tag, Known: known}
+				}
+				tags[tag].Packages = append(tags[tag].Packages, &model.TagPackage{Type: typ, Repo: repoPath})
+			}
+
+			if 0 < len(result.Normalized) {
+				var changes []string
+				for _, change := range result.Normalized {
+					changes = append(changes, "`"+change.From+"` → `"+change.To+"`")
+				}
+				stageReport.add("Normalized keywords", "[%s](https://github.com/%s): %s", repoPath, repoPath, strings.Join(changes, ", "))
+			}
+			if 0 < len(result.Unknown) {
+				stageReport.add("Unknown keywords", "[%s](https://github.com/%s): `%s`", repoPath, repoPath, strings.Join(result.Unknown, "`, `"))
+			}
+		}
+	}
+
+	index := &model.TagsIndex{Tags: make([]*model.Tag, 0, len(tags))}
+	for _, tag := range tags {
+		sort.Slice(tag.Packages, func(i, j int) bool {
+			return tag.Packages[i].Repo < tag.Packages[j].Repo
+		})
+		index.Tags = append(index.Tags, tag)
+	}
+	sort.Slice(index.Tags, func(i, j int) bool {
+		if len(index.Tags[i].Packages) != len(index.Tags[j].Packages) {
+			return len(index.Tags[i].Packages) > len(index.Tags[j].Packages)
+		}
+		return index.Tags[i].Tag < index.Tags[j].Tag
+	})
+
+	if err = model.SaveTagsIndex("stage/"+model.TagsName+".json", index); nil != err {
+		logger.Fatalf("write stage [%s.json] failed: %s", model.TagsName, err)
+	}
+
+	logger.Infof("staged [%s]", model.TagsName)
+}
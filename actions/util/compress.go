@@ -0,0 +1,69 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package util
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Encoding 预压缩编码，Name 为 HTTP Content-Encoding 取值，Ext 为上传 key 的后缀
+type Encoding struct {
+	Name string
+	Ext  string
+}
+
+// Encodings 索引文件的预压缩编码，上传 key 为原 key 加上 Ext，如 stage/plugins.json.br
+var Encodings = []Encoding{
+	{Name: "gzip", Ext: ".gz"},
+	{Name: "br", Ext: ".br"},
+	{Name: "zstd", Ext: ".zst"},
+}
+
+// Compress 使用指定编码以最高压缩率压缩数据
+func Compress(encoding string, data []byte) (ret []byte, err error) {
+	buf := &bytes.Buffer{}
+	switch encoding {
+	case "gzip":
+		w, _ := gzip.NewWriterLevel(buf, gzip.BestCompression)
+		if _, err = w.Write(data); nil != err {
+			return
+		}
+		err = w.Close()
+	case "br":
+		w := brotli.NewWriterLevel(buf, brotli.BestCompression)
+		if _, err = w.Write(data); nil != err {
+			return
+		}
+		err = w.Close()
+	case "zstd":
+		var w *zstd.Encoder
+		if w, err = zstd.NewWriter(buf, zstd.WithEncoderLevel(zstd.SpeedBestCompression)); nil != err {
+			return
+		}
+		if _, err = w.Write(data); nil != err {
+			w.Close()
+			return
+		}
+		err = w.Close()
+	default:
+		err = fmt.Errorf("unsupported encoding [%s]", encoding)
+	}
+	if nil != err {
+		return
+	}
+	ret = buf.Bytes()
+	return
+}
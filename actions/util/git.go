@@ -0,0 +1,34 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package util
+
+import (
+	"errors"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// StageHashes 返回最近 n 个修改了 stage 目录的提交，最新的在前。
+// bazaar hash 取其中第一个：只有 stage 数据变化时才会变化，列表文件等其他提交不会让客户端重新下载索引；
+// 第二个即上一次发布的 bazaar hash，作为增量的起点。需要完整的提交历史（签出时 fetch-depth: 0）
+func StageHashes(n int) (ret []string, err error) {
+	cmd := exec.Command("git", "log", "-n", strconv.Itoa(n), "--format=%H", "--", "stage")
+	data, err := cmd.CombinedOutput()
+	if nil != err {
+		return nil, errors.New(strings.TrimSpace(string(data)))
+	}
+	ret = strings.Fields(string(data))
+	if 0 == len(ret) {
+		return nil, errors.New("no commit changed stage")
+	}
+	return
+}
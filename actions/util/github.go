@@ -0,0 +1,48 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package util
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+// GetCanonicalRepo 查询仓库的规范名称 owner/repo。仓库改名或转移后 GitHub 会将旧名称重定向到新仓库，
+// 此时返回的 fullName 与 ownerRepo 不同；仓库不存在时 notFound 为 true
+func GetCanonicalRepo(ownerRepo string) (fullName string, notFound bool, err error) {
+	result := &struct {
+		FullName string `json:"full_name"`
+	}{}
+	// REF https://docs.github.com/en/rest/repos/repos#get-a-repository
+	u := "https://api.github.com/repos/" + ownerRepo
+	resp, _, errs := gorequest.New().Get(u).
+		Set("Authorization", "Token "+os.Getenv("PAT")).
+		Set("User-Agent", UserAgent).
+		Retry(1, 3*time.Second).Timeout(30 * time.Second).EndStruct(result)
+	if nil != errs {
+		err = fmt.Errorf("get [%s] failed: %s", u, errs)
+		return
+	}
+	if http.StatusNotFound == resp.StatusCode {
+		notFound = true
+		return
+	}
+	if http.StatusOK != resp.StatusCode || "" == result.FullName {
+		err = fmt.Errorf("get [%s] failed: %d", u, resp.StatusCode)
+		return
+	}
+	fullName = result.FullName
+	return
+}
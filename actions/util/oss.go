@@ -25,23 +25,17 @@ import (
 
 var logger = gulu.Log.NewLogger(os.Stdout)
 
+// OSSServer 集市 OSS 的 CDN 地址，上传的 key 可通过 OSSServer + "/" + key 访问
+const OSSServer = "https://oss.b3logfile.com"
+
 func UploadOSS(key, contentType string, data []byte) (err error) {
 	bucket := os.Getenv("QINIU_BUCKET")
 	ak := os.Getenv("QINIU_AK")
 	sk := os.Getenv("QINIU_SK")
 
 	cfg := storage.Config{UseCdnDomains: true, UseHTTPS: true}
-	mac := qbox.NewMac(ak, sk)
-	bucketManager := storage.NewBucketManager(mac, &cfg)
-	stat, err := bucketManager.Stat(bucket, key)
-	if nil != err {
-		if !strings.Contains(err.Error(), "no such file or directory") {
-			logger.Warnf("stat [%s] failed: %s", key, err)
-		}
-	} else {
-		if "" != stat.Hash {
-			return
-		}
+	if ossExists(bucket, ak, sk, key) {
+		return
 	}
 
 	putPolicy := storage.PutPolicy{
@@ -62,6 +56,48 @@ func UploadOSS(key, contentType string, data []byte) (err error) {
 	return
 }
 
+// UploadOSSEncoded 上传预压缩的数据，对象的 Content-Type 为解压后的内容类型，Content-Encoding 为 contentEncoding（gzip、br、zstd 等），
+// 客户端按标准 HTTP 语义解压。七牛原生上传接口不支持写入 Content-Encoding，因此通过存储空间的 S3 兼容接口上传
+func UploadOSSEncoded(key, contentType, contentEncoding string, data []byte) (err error) {
+	bucket := os.Getenv("QINIU_BUCKET")
+	ak := os.Getenv("QINIU_AK")
+	sk := os.Getenv("QINIU_SK")
+
+	if ossExists(bucket, ak, sk, key) {
+		return
+	}
+
+	endpoint, err := getS3Endpoint(bucket, ak)
+	if nil != err {
+		logger.Errorf("get s3 endpoint of bucket [%s] failed: %s", bucket, err)
+		return
+	}
+	header := map[string]string{"Content-Type": contentType, "Content-Encoding": contentEncoding}
+	if err = putS3Object(endpoint, ak, sk, bucket, key, header, data); nil != err {
+		logger.Warnf("upload [%s] failed: %s, retry it", key, err)
+		if err = putS3Object(endpoint, ak, sk, bucket, key, header, data); nil != err {
+			logger.Errorf("retry upload [%s] failed: %s", key, err)
+			return
+		}
+		logger.Infof("retry upload [%s] success", key)
+	}
+	return
+}
+
+// ossExists 判断对象是否已存在，已存在的对象不重复上传
+func ossExists(bucket, ak, sk, key string) bool {
+	cfg := storage.Config{UseCdnDomains: true, UseHTTPS: true}
+	bucketManager := storage.NewBucketManager(qbox.NewMac(ak, sk), &cfg)
+	stat, err := bucketManager.Stat(bucket, key)
+	if nil != err {
+		if !strings.Contains(err.Error(), "no such file or directory") {
+			logger.Warnf("stat [%s] failed: %s", key, err)
+		}
+		return false
+	}
+	return "" != stat.Hash
+}
+
 const UserAgent = "bazaar/1.0.0 https://github.com/siyuan-note/bazaar"
 
 func SizeOfDirectory(path string) (size int64, err error) {
@@ -11,46 +11,138 @@
 package util
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"strings"
 )
 
-// ParseReposFromTxt 从 TXT 文件解析包列表：按行分割、过滤空行，校验每行为合法 owner/repo，返回 []string。
-// 不做 TrimSpace，行首尾或 owner/repo 首尾含空格均视为解析错误。兼容多种换行符（\n、\r\n、\r）。
-func ParseReposFromTxt(filePath string) (repos []string, err error) {
+// 包列表条目的标注
+const (
+	AttrPinned = "pinned" // pinned=<tag>：使用指定 tag 的发布而不是最新发布
+	AttrHidden = "hidden" // hidden：暂不上架，stage 时跳过
+)
+
+// RepoEntry 包列表中的一个条目
+type RepoEntry struct {
+	Repo  string            // owner/repo
+	Owner string            // 仓库拥有者
+	Name  string            // 仓库名
+	Line  int               // 所在行号，从 1 开始
+	Attrs map[string]string // 行尾标注，如 pinned=v1.2.0；不带值的标注（如 hidden）值为空字符串
+	Note  string            // 标注之后的说明文字，如 pinned 的原因
+}
+
+// Has 判断条目是否带有标注
+func (entry *RepoEntry) Has(attr string) bool {
+	_, ok := entry.Attrs[attr]
+	return ok
+}
+
+// Attr 返回标注的值，不存在或不带值时返回空字符串
+func (entry *RepoEntry) Attr(attr string) string {
+	return entry.Attrs[attr]
+}
+
+// ParseReposFromTxt 从 TXT 文件解析包列表，返回按行排列的条目。每行为以下之一：
+//   - 空行；
+//   - 以 # 开头的注释行；
+//   - owner/repo，其后可带行尾标注，如 `owner/repo  # pinned=v1.2.0 hidden upstream v1.3 breaks sync`。
+//
+// # 之后依次识别空白分隔的 pinned=<tag>、hidden，从第一个无法识别的词或第二个 # 开始均为说明文字（Note），不会报错。
+//
+// 不做 TrimSpace，行首尾或 owner/repo 首尾含空格均视为解析错误，错误信息带有行号。兼容多种换行符（\n、\r\n、\r）。
+func ParseReposFromTxt(filePath string) (entries []*RepoEntry, err error) {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("read file: %w", err)
 	}
-	scanner := bufio.NewScanner(strings.NewReader(string(data)))
-	repos = make([]string, 0)
-	lineNum := 0
-	for scanner.Scan() {
-		lineNum++
-		line := scanner.Text()
-		if line == "" {
-			continue
+	entries = make([]*RepoEntry, 0)
+	for i, line := range splitLines(string(data)) {
+		entry, parseErr := parseRepoLine(line, i+1)
+		if parseErr != nil {
+			return nil, parseErr
 		}
-		if line != strings.TrimSpace(line) {
-			return nil, fmt.Errorf("line %d: leading or trailing space not allowed: %q", lineNum, line)
+		if entry != nil {
+			entries = append(entries, entry)
 		}
-		parts := strings.Split(line, "/")
-		if len(parts) != 2 {
-			return nil, fmt.Errorf("line %d: invalid format (expected owner/repo): %q", lineNum, line)
+	}
+	return entries, nil
+}
+
+// parseRepoLine 解析一行，空行与注释行返回 nil
+func parseRepoLine(line string, lineNum int) (*RepoEntry, error) {
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil, nil
+	}
+	if line != strings.TrimSpace(line) {
+		return nil, fmt.Errorf("line %d: leading or trailing space not allowed: %q", lineNum, line)
+	}
+
+	repoPart, annotation, annotated := strings.Cut(line, "#")
+	if annotated {
+		if repoPart == strings.TrimRight(repoPart, " \t") {
+			return nil, fmt.Errorf("line %d: annotation must be separated from owner/repo by whitespace: %q", lineNum, line)
 		}
-		owner, repo := parts[0], parts[1]
-		if owner == "" || repo == "" {
-			return nil, fmt.Errorf("line %d: invalid format (owner and repo must be non-empty): %q", lineNum, line)
+		repoPart = strings.TrimRight(repoPart, " \t")
+	}
+	parts := strings.Split(repoPart, "/")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("line %d: invalid format (expected owner/repo): %q", lineNum, line)
+	}
+	owner, name := parts[0], parts[1]
+	if owner == "" || name == "" {
+		return nil, fmt.Errorf("line %d: invalid format (owner and repo must be non-empty): %q", lineNum, line)
+	}
+	if strings.ContainsAny(repoPart, " \t") {
+		return nil, fmt.Errorf("line %d: leading or trailing space in owner/repo not allowed: %q", lineNum, line)
+	}
+
+	entry := &RepoEntry{Repo: owner + "/" + name, Owner: owner, Name: name, Line: lineNum, Attrs: map[string]string{}}
+	fields := strings.Fields(annotation)
+	for i, field := range fields {
+		key, value, hasValue := strings.Cut(field, "=")
+		known := (key == AttrPinned && hasValue && value != "") || field == AttrHidden
+		if !known {
+			entry.Note = strings.TrimSpace(strings.TrimPrefix(strings.Join(fields[i:], " "), "#"))
+			break
 		}
-		if owner != strings.TrimSpace(owner) || repo != strings.TrimSpace(repo) {
-			return nil, fmt.Errorf("line %d: leading or trailing space in owner/repo not allowed: %q", lineNum, line)
+		entry.Attrs[key] = value
+	}
+	return entry, nil
+}
+
+// RewriteReposTxt 逐行改写包列表文件，换行符统一为 \n：rewrite 接收每个条目，返回替换后的 owner/repo，
+// 返回空字符串时删除该行；行尾标注、注释行与空行原样保留
+func RewriteReposTxt(filePath string, rewrite func(entry *RepoEntry) string) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("read file: %w", err)
+	}
+	lines := splitLines(string(data))
+	ret := make([]string, 0, len(lines))
+	for i, line := range lines {
+		entry, parseErr := parseRepoLine(line, i+1)
+		if parseErr != nil {
+			return parseErr
+		}
+		if entry == nil {
+			ret = append(ret, line)
+			continue
+		}
+		repo := rewrite(entry)
+		if repo == "" {
+			continue
 		}
-		repos = append(repos, owner+"/"+repo)
+		ret = append(ret, repo+strings.TrimPrefix(line, entry.Repo))
 	}
-	if err = scanner.Err(); err != nil {
-		return nil, fmt.Errorf("read file: %w", err)
+	if err = os.WriteFile(filePath, []byte(strings.Join(ret, "\n")), 0644); err != nil {
+		return fmt.Errorf("write file: %w", err)
 	}
-	return repos, nil
+	return nil
+}
+
+// splitLines 按 \n、\r\n、\r 分割行，末尾换行产生的空行保留，以便原样写回
+func splitLines(content string) []string {
+	content = strings.ReplaceAll(strings.ReplaceAll(content, "\r\n", "\n"), "\r", "\n")
+	return strings.Split(content, "\n")
 }
@@ -0,0 +1,87 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseRepoLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    *RepoEntry // nil 表示空行或注释行
+		wantErr bool
+	}{
+		{name: "empty", line: ""},
+		{name: "comment", line: "# plugins"},
+		{name: "plain", line: "a/b", want: &RepoEntry{Repo: "a/b", Owner: "a", Name: "b", Attrs: map[string]string{}}},
+		{name: "pinned", line: "a/b  # pinned=v1.2.0", want: &RepoEntry{Repo: "a/b", Owner: "a", Name: "b", Attrs: map[string]string{AttrPinned: "v1.2.0"}}},
+		{name: "pinned and hidden", line: "a/b # pinned=v1.2.0 hidden", want: &RepoEntry{Repo: "a/b", Owner: "a", Name: "b", Attrs: map[string]string{AttrPinned: "v1.2.0", AttrHidden: ""}}},
+		{name: "note after annotations", line: "a/b  # pinned=v1.2.0 because upstream broke", want: &RepoEntry{Repo: "a/b", Owner: "a", Name: "b", Attrs: map[string]string{AttrPinned: "v1.2.0"}, Note: "because upstream broke"}},
+		{name: "capitalized note", line: "a/b # pinned=v1.0.0 Upstream v1.1 breaks sync.", want: &RepoEntry{Repo: "a/b", Owner: "a", Name: "b", Attrs: map[string]string{AttrPinned: "v1.0.0"}, Note: "Upstream v1.1 breaks sync."}},
+		{name: "note after second hash", line: "a/b # hidden # hidden=until review", want: &RepoEntry{Repo: "a/b", Owner: "a", Name: "b", Attrs: map[string]string{AttrHidden: ""}, Note: "hidden=until review"}},
+		{name: "note only", line: "a/b # maintained by the community", want: &RepoEntry{Repo: "a/b", Owner: "a", Name: "b", Attrs: map[string]string{}, Note: "maintained by the community"}},
+		{name: "pinned without tag is a note", line: "a/b # pinned until v2", want: &RepoEntry{Repo: "a/b", Owner: "a", Name: "b", Attrs: map[string]string{}, Note: "pinned until v2"}},
+		{name: "empty annotation", line: "a/b #", want: &RepoEntry{Repo: "a/b", Owner: "a", Name: "b", Attrs: map[string]string{}}},
+		{name: "hash without space", line: "a/b#hidden", wantErr: true},
+		{name: "leading space", line: " a/b", wantErr: true},
+		{name: "trailing space", line: "a/b ", wantErr: true},
+		{name: "missing repo", line: "a/", wantErr: true},
+		{name: "too many segments", line: "a/b/c", wantErr: true},
+		{name: "space inside", line: "a /b", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRepoLine(tt.line, 3)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRepoLine(%q) error = %v, wantErr %v", tt.line, err, tt.wantErr)
+			}
+			if tt.want != nil {
+				tt.want.Line = 3
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseRepoLine(%q) = %+v, want %+v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRewriteReposTxt(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "plugins.txt")
+	data := "# plugins\r\na/b\r\n\r\nc/d  # pinned=v1.0.0 upstream broke\ne/f\n"
+	if err := os.WriteFile(filePath, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := RewriteReposTxt(filePath, func(entry *RepoEntry) string {
+		switch entry.Repo {
+		case "a/b":
+			return ""
+		case "c/d":
+			return "x/d"
+		}
+		return entry.Repo
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "# plugins\n\nx/d  # pinned=v1.0.0 upstream broke\ne/f\n"; string(got) != want {
+		t.Errorf("RewriteReposTxt() = %q, want %q", got, want)
+	}
+}
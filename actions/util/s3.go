@@ -0,0 +1,148 @@
+// SiYuan community bazaar.
+// Copyright (c) 2021-present, b3log.org
+//
+// Bazaar is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qiniu/go-sdk/v7/storagev2/apis"
+	httpclient "github.com/qiniu/go-sdk/v7/storagev2/http_client"
+)
+
+// s3Endpoint 存储空间的 S3 兼容接口地址。七牛原生上传接口只能写入 x-qn-meta- 自定义元信息，
+// S3 兼容接口的 PutObject 可以写入 Content-Encoding 等标准响应头
+type s3Endpoint struct {
+	host   string // S3 域名，如 s3.cn-east-1.qiniucs.com
+	region string // S3 Region，如 cn-east-1
+}
+
+var (
+	s3EndpointOnce sync.Once
+	s3EndpointRet  *s3Endpoint
+	s3EndpointErr  error
+)
+
+// getS3Endpoint 查询存储空间的 S3 域名及 Region，进程内只查询一次
+func getS3Endpoint(bucket, ak string) (*s3Endpoint, error) {
+	s3EndpointOnce.Do(func() {
+		resp, err := apis.NewStorage(&httpclient.Options{}).QueryBucketV4(context.Background(),
+			&apis.QueryBucketV4Request{Bucket: bucket, AccessKey: ak}, nil)
+		if nil != err {
+			s3EndpointErr = err
+			return
+		}
+		for _, host := range resp.Hosts {
+			if "" != host.S3Domains.RegionAlias && 0 < len(host.S3Domains.PreferedApiDomains) {
+				s3EndpointRet = &s3Endpoint{host: host.S3Domains.PreferedApiDomains[0], region: host.S3Domains.RegionAlias}
+				return
+			}
+		}
+		s3EndpointErr = errors.New("no s3 domain for bucket [" + bucket + "]")
+	})
+	return s3EndpointRet, s3EndpointErr
+}
+
+// putS3Object 通过 S3 兼容接口（路径风格、AWS Signature V4）上传对象，header 为需要写入对象的标准响应头
+func putS3Object(endpoint *s3Endpoint, ak, sk, bucket, key string, header map[string]string, data []byte) error {
+	host := strings.TrimPrefix(strings.TrimPrefix(endpoint.host, "https://"), "http://")
+	canonicalURI := "/" + s3EscapePath(bucket) + "/" + s3EscapePath(key)
+	req, err := http.NewRequest(http.MethodPut, "https://"+host+canonicalURI, bytes.NewReader(data))
+	if nil != err {
+		return err
+	}
+	for name, value := range header {
+		req.Header.Set(name, value)
+	}
+	signS3Request(req, host, canonicalURI, ak, sk, endpoint.region, data, time.Now().UTC())
+
+	resp, err := (&http.Client{Timeout: 60 * time.Second}).Do(req)
+	if nil != err {
+		return err
+	}
+	defer resp.Body.Close()
+	if http.StatusOK != resp.StatusCode {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("put [%s] failed: %d %s", key, resp.StatusCode, body)
+	}
+	return nil
+}
+
+// signS3Request 按 AWS Signature V4 为请求签名，签名所有已设置的请求头及 host、x-amz-content-sha256、x-amz-date
+func signS3Request(req *http.Request, host, canonicalURI, ak, sk, region string, data []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(data)
+	req.Host = host
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	canonicalHeaders := &strings.Builder{}
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{req.Method, canonicalURI, "", canonicalHeaders.String(), signedHeaders, payloadHash}, "\n")
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+sk), date)
+	for _, part := range []string{region, "s3", "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+ak+"/"+scope+", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+// s3EscapePath 按 S3 规则转义对象路径，只保留非保留字符及路径分隔符
+func s3EscapePath(p string) string {
+	buf := &strings.Builder{}
+	for _, b := range []byte(p) {
+		if ('A' <= b && 'Z' >= b) || ('a' <= b && 'z' >= b) || ('0' <= b && '9' >= b) || 0 <= strings.IndexByte("-_.~/", b) {
+			buf.WriteByte(b)
+			continue
+		}
+		fmt.Fprintf(buf, "%%%02X", b)
+	}
+	return buf.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
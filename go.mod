@@ -4,11 +4,19 @@ go 1.25.4
 
 require (
 	github.com/88250/gulu v1.2.3-0.20241212012748-c4dc08fe45ec
+	github.com/HugoSmits86/nativewebp v0.9.3
+	github.com/andybalholm/brotli v1.2.6
 	github.com/google/go-github/v52 v52.0.0
+	github.com/gorilla/css v1.0.1
+	github.com/klauspost/compress v1.20.1
 	github.com/microcosm-cc/bluemonday v1.0.27
 	github.com/panjf2000/ants/v2 v2.11.5
 	github.com/parnurzeal/gorequest v0.3.0
 	github.com/qiniu/go-sdk/v7 v7.25.6
+	github.com/tdewolff/parse/v2 v2.8.16
+	github.com/yuin/goldmark v1.8.6
+	golang.org/x/image v0.34.0
+	golang.org/x/text v0.32.0
 )
 
 require (
@@ -22,7 +30,6 @@ require (
 	github.com/gofrs/flock v0.12.1 // indirect
 	github.com/google/go-cmp v0.6.0 // indirect
 	github.com/google/go-querystring v1.1.0 // indirect
-	github.com/gorilla/css v1.0.1 // indirect
 	github.com/moul/http2curl v1.0.0 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/smartystreets/goconvey v1.8.1 // indirect
@@ -31,6 +38,5 @@ require (
 	golang.org/x/oauth2 v0.30.0 // indirect
 	golang.org/x/sync v0.19.0 // indirect
 	golang.org/x/sys v0.39.0 // indirect
-	golang.org/x/text v0.32.0 // indirect
 	modernc.org/fileutil v1.3.4 // indirect
 )
@@ -15,7 +15,10 @@ Please fix the corresponding `.txt` file in the repo root: one `owner/repo` per
 {{ range $i, $repo := .Icons }}
 #### [{{ $repo.RepoInfo.Path }}]({{ $repo.RepoInfo.Home }}){{ if $repo.MaintainerChanged }} (Change Maintainer){{ end }}
 
-- {{ if $repo.Release.Pass }}[x]{{ else }}[ ]{{ end }} Release that must exist
+- {{ if $repo.RepoInfo.Health.Pass }}[x]{{ else }}[ ]{{ end }} Repository status
+{{ if $repo.RepoInfo.Health.Error }}  - [ ] {{ $repo.RepoInfo.Health.Error }}
+{{ end }}{{ range $repo.RepoInfo.Health.Items }}  - {{ if .Pass }}[x]{{ else }}[ ]{{ end }} {{ .Name }}{{ if .Detail }}: {{ .Detail }}{{ end }}
+{{ end }}- {{ if $repo.Release.Pass }}[x]{{ else }}[ ]{{ end }} Release that must exist
   - {{ if $repo.Release.LatestRelease.Pass }}[x] [Current Latest Release]({{ $repo.Release.LatestRelease.URL }}){{ else }}[ ] Current Latest Release{{ end }}
   - {{ if $repo.Release.LatestRelease.PackageZip.Pass }}[x] [package.zip]({{ $repo.Release.LatestRelease.PackageZip.URL }}){{ else }}[ ] `package.zip`{{ end }}
 - {{ if $repo.Files.Pass }}[x]{{ else }}[ ]{{ end }} Files that must exist
@@ -23,14 +26,28 @@ Please fix the corresponding `.txt` file in the repo root: one `owner/repo` per
   - {{ if $repo.Files.IconPng.Pass }}[x] [icon.png]({{ $repo.Files.IconPng.URL }}){{ else }}[ ] `icon.png`{{ end }}
   - {{ if $repo.Files.PreviewPng.Pass }}[x] [preview.png]({{ $repo.Files.PreviewPng.URL }}){{ else }}[ ] `preview.png`{{ end }}
   - {{ if $repo.Files.ReadmeMd.Pass }}[x] [README.md]({{ $repo.Files.ReadmeMd.URL }}){{ else }}[ ] `README.md`{{ end }}
-- {{ if $repo.Attrs.Pass }}[x]{{ else }}[ ]{{ end }} Attributes that must exist in `icon.json`
+- {{ if $repo.Content.Pass }}[x]{{ else }}[ ]{{ end }} Content that must exist in `package.zip`
+{{ if $repo.Content.Error }}  - [ ] {{ $repo.Content.Error }}
+{{ end }}{{ range $repo.Content.Rules }}  - {{ if .Pass }}[x]{{ else }}[ ]{{ end }} `{{ .Name }}`
+{{ end }}{{ with $repo.Content.Syntax }}  - [ ] `{{ .File }}` has a JavaScript syntax error{{ if .Line }} at line {{ .Line }}, column {{ .Column }}{{ end }}: {{ .Message }}
+{{ end }}- {{ if $repo.Secrets.Pass }}[x]{{ else }}[ ]{{ end }} No secrets or credentials in `package.zip` or the repository
+{{ if $repo.Secrets.Error }}  - [ ] {{ $repo.Secrets.Error }}
+{{ end }}{{ range $repo.Secrets.Findings }}  - [ ] {{ .Kind }} in {{ .Source }} `{{ .File }}`{{ if .Line }} line {{ .Line }}{{ end }}{{ if .Redacted }}: `{{ .Redacted }}`{{ end }}
+{{ end }}- {{ if $repo.License.Pass }}[x] License: `{{ $repo.License.ID }}`{{ else }}[ ] A recognized license in `LICENSE`{{ end }}
+{{ range $repo.License.Files }}  - {{ if .ID }}[x]{{ else }}[ ]{{ end }} {{ if .URL }}[{{ .File }}]({{ .URL }}){{ else }}`{{ .File }}`{{ end }} in {{ .Source }}: {{ if .ID }}`{{ .ID }}`{{ else }}license not recognized{{ end }}
+{{ end }}- {{ if $repo.Attrs.Pass }}[x]{{ else }}[ ]{{ end }} Attributes that must exist in `icon.json`
   - {{ if $repo.Attrs.Name.Pass }}[x] `name`: `{{ $repo.Attrs.Name.Value }}`{{ else }}[ ] `name`{{ end }}
     - {{ if $repo.Attrs.Name.Valid }}[x]{{ else }}[ ]{{ end }} Is a valid name
     - {{ if $repo.Attrs.Name.Unique }}[x]{{ else }}[ ]{{ end }} Not conflict with other icon name
   - {{ if $repo.Attrs.Version.Pass }}[x] `version`: `{{ $repo.Attrs.Version.Value }}`{{ else }}[ ] `version`{{ end }}
   - {{ if $repo.Attrs.Author.Pass }}[x] `author`: `{{ $repo.Attrs.Author.Value }}`{{ else }}[ ] `author`{{ end }}
   - {{ if $repo.Attrs.URL.Pass }}[x] `url`: [{{ $repo.Attrs.URL.Value }}]({{ $repo.Attrs.URL.Value }}){{ else }}[ ] `url`{{ end }}
-
+{{ if $repo.Attrs.Readme.Files }}  - {{ if $repo.Attrs.Readme.Pass }}[x]{{ else }}[ ]{{ end }} `readme`: README files that must exist
+{{ range $repo.Attrs.Readme.Files }}    - {{ if .Pass }}[x] `{{ .Locale }}`: [{{ .Path }}]({{ .URL }}){{ else }}[ ] `{{ .Locale }}`: `{{ .Path }}`{{ end }}
+{{ end }}{{ end }}{{ if or $repo.Attrs.Keywords.Normalized $repo.Attrs.Keywords.Unknown }}- Keywords in `icon.json` (for reference only)
+{{ range $repo.Attrs.Keywords.Normalized }}  - `{{ .From }}` is normalized to `{{ .To }}`
+{{ end }}{{ range $repo.Attrs.Keywords.Unknown }}  - `{{ . }}` is not in [keywords.json](https://github.com/siyuan-note/bazaar/blob/main/keywords.json)
+{{ end }}{{ end }}
 ---
 {{ end }}
 {{ end }}
@@ -49,7 +66,10 @@ Please fix the corresponding `.txt` file in the repo root: one `owner/repo` per
 {{ range $i, $repo := .Plugins }}
 #### [{{ $repo.RepoInfo.Path }}]({{ $repo.RepoInfo.Home }}){{ if $repo.MaintainerChanged }} (Change Maintainer){{ end }}
 
-- {{ if $repo.Release.Pass }}[x]{{ else }}[ ]{{ end }} Release that must exist
+- {{ if $repo.RepoInfo.Health.Pass }}[x]{{ else }}[ ]{{ end }} Repository status
+{{ if $repo.RepoInfo.Health.Error }}  - [ ] {{ $repo.RepoInfo.Health.Error }}
+{{ end }}{{ range $repo.RepoInfo.Health.Items }}  - {{ if .Pass }}[x]{{ else }}[ ]{{ end }} {{ .Name }}{{ if .Detail }}: {{ .Detail }}{{ end }}
+{{ end }}- {{ if $repo.Release.Pass }}[x]{{ else }}[ ]{{ end }} Release that must exist
   - {{ if $repo.Release.LatestRelease.Pass }}[x] [Current Latest Release]({{ $repo.Release.LatestRelease.URL }}){{ else }}[ ] Current Latest Release{{ end }}
   - {{ if $repo.Release.LatestRelease.PackageZip.Pass }}[x] [package.zip]({{ $repo.Release.LatestRelease.PackageZip.URL }}){{ else }}[ ] `package.zip`{{ end }}
 - {{ if $repo.Files.Pass }}[x]{{ else }}[ ]{{ end }} Files that must exist
@@ -57,7 +77,18 @@ Please fix the corresponding `.txt` file in the repo root: one `owner/repo` per
   - {{ if $repo.Files.IconPng.Pass }}[x] [icon.png]({{ $repo.Files.IconPng.URL }}){{ else }}[ ] `icon.png`{{ end }}
   - {{ if $repo.Files.PreviewPng.Pass }}[x] [preview.png]({{ $repo.Files.PreviewPng.URL }}){{ else }}[ ] `preview.png`{{ end }}
   - {{ if $repo.Files.ReadmeMd.Pass }}[x] [README.md]({{ $repo.Files.ReadmeMd.URL }}){{ else }}[ ] `README.md`{{ end }}
-- {{ if $repo.Attrs.Pass }}[x]{{ else }}[ ]{{ end }} Attributes that must exist in `plugin.json`
+- {{ if $repo.Content.Pass }}[x]{{ else }}[ ]{{ end }} Content that must exist in `package.zip`
+{{ if $repo.Content.Error }}  - [ ] {{ $repo.Content.Error }}
+{{ end }}{{ range $repo.Content.Rules }}  - {{ if .Pass }}[x]{{ else }}[ ]{{ end }} `{{ .Name }}`
+{{ end }}{{ with $repo.Content.Syntax }}  - [ ] `{{ .File }}` has a JavaScript syntax error{{ if .Line }} at line {{ .Line }}, column {{ .Column }}{{ end }}: {{ .Message }}
+{{ end }}{{ if $repo.Content.Security }}- :mag: Security scan findings for reviewers (informational, do not block)
+{{ range $repo.Content.Security }}  - **{{ .Severity }}** `{{ .File }}` line {{ .Line }}{{ if gt .Count 1 }} ({{ .Count }} matches){{ end }}: {{ .Message }}: `{{ .Evidence }}`
+{{ end }}{{ end }}- {{ if $repo.Secrets.Pass }}[x]{{ else }}[ ]{{ end }} No secrets or credentials in `package.zip` or the repository
+{{ if $repo.Secrets.Error }}  - [ ] {{ $repo.Secrets.Error }}
+{{ end }}{{ range $repo.Secrets.Findings }}  - [ ] {{ .Kind }} in {{ .Source }} `{{ .File }}`{{ if .Line }} line {{ .Line }}{{ end }}{{ if .Redacted }}: `{{ .Redacted }}`{{ end }}
+{{ end }}- {{ if $repo.License.Pass }}[x] License: `{{ $repo.License.ID }}`{{ else }}[ ] A recognized license in `LICENSE`{{ end }}
+{{ range $repo.License.Files }}  - {{ if .ID }}[x]{{ else }}[ ]{{ end }} {{ if .URL }}[{{ .File }}]({{ .URL }}){{ else }}`{{ .File }}`{{ end }} in {{ .Source }}: {{ if .ID }}`{{ .ID }}`{{ else }}license not recognized{{ end }}
+{{ end }}- {{ if $repo.Attrs.Pass }}[x]{{ else }}[ ]{{ end }} Attributes that must exist in `plugin.json`
   - {{ if $repo.Attrs.Name.Pass }}[x] `name`: `{{ $repo.Attrs.Name.Value }}`{{ else }}[ ] `name`{{ end }}
     - {{ if $repo.Attrs.Name.Exist }}[x]{{ else }}[ ]{{ end }} The attribute exists
     - {{ if $repo.Attrs.Name.Valid }}[x]{{ else }}[ ]{{ end }} Is a valid name
@@ -65,7 +96,13 @@ Please fix the corresponding `.txt` file in the repo root: one `owner/repo` per
   - {{ if $repo.Attrs.Version.Pass }}[x] `version`: `{{ $repo.Attrs.Version.Value }}`{{ else }}[ ] `version`{{ end }}
   - {{ if $repo.Attrs.Author.Pass }}[x] `author`: `{{ $repo.Attrs.Author.Value }}`{{ else }}[ ] `author`{{ end }}
   - {{ if $repo.Attrs.URL.Pass }}[x] `url`: [{{ $repo.Attrs.URL.Value }}]({{ $repo.Attrs.URL.Value }}){{ else }}[ ] `url`{{ end }}
-
+{{ if $repo.Attrs.Readme.Files }}  - {{ if $repo.Attrs.Readme.Pass }}[x]{{ else }}[ ]{{ end }} `readme`: README files that must exist
+{{ range $repo.Attrs.Readme.Files }}    - {{ if .Pass }}[x] `{{ .Locale }}`: [{{ .Path }}]({{ .URL }}){{ else }}[ ] `{{ .Locale }}`: `{{ .Path }}`{{ end }}
+{{ end }}{{ end }}{{ range $repo.Attrs.Enums.Invalid }}  - [ ] `{{ .Field }}` has unknown values: {{ range $i, $v := .Values }}{{ if $i }}, {{ end }}`{{ $v }}`{{ end }} (allowed: {{ range $i, $v := .Allowed }}{{ if $i }}, {{ end }}`{{ $v }}`{{ end }})
+{{ end }}{{ if or $repo.Attrs.Keywords.Normalized $repo.Attrs.Keywords.Unknown }}- Keywords in `plugin.json` (for reference only)
+{{ range $repo.Attrs.Keywords.Normalized }}  - `{{ .From }}` is normalized to `{{ .To }}`
+{{ end }}{{ range $repo.Attrs.Keywords.Unknown }}  - `{{ . }}` is not in [keywords.json](https://github.com/siyuan-note/bazaar/blob/main/keywords.json)
+{{ end }}{{ end }}
 ---
 {{ end }}
 {{ end }}
@@ -84,7 +121,10 @@ Please fix the corresponding `.txt` file in the repo root: one `owner/repo` per
 {{ range $i, $repo := .Templates }}
 #### [{{ $repo.RepoInfo.Path }}]({{ $repo.RepoInfo.Home }}){{ if $repo.MaintainerChanged }} (Change Maintainer){{ end }}
 
-- {{ if $repo.Release.Pass }}[x]{{ else }}[ ]{{ end }} Release that must exist
+- {{ if $repo.RepoInfo.Health.Pass }}[x]{{ else }}[ ]{{ end }} Repository status
+{{ if $repo.RepoInfo.Health.Error }}  - [ ] {{ $repo.RepoInfo.Health.Error }}
+{{ end }}{{ range $repo.RepoInfo.Health.Items }}  - {{ if .Pass }}[x]{{ else }}[ ]{{ end }} {{ .Name }}{{ if .Detail }}: {{ .Detail }}{{ end }}
+{{ end }}- {{ if $repo.Release.Pass }}[x]{{ else }}[ ]{{ end }} Release that must exist
   - {{ if $repo.Release.LatestRelease.Pass }}[x] [Current Latest Release]({{ $repo.Release.LatestRelease.URL }}){{ else }}[ ] Current Latest Release{{ end }}
   - {{ if $repo.Release.LatestRelease.PackageZip.Pass }}[x] [package.zip]({{ $repo.Release.LatestRelease.PackageZip.URL }}){{ else }}[ ] `package.zip`{{ end }}
 - {{ if $repo.Files.Pass }}[x]{{ else }}[ ]{{ end }} Files that must exist
@@ -92,14 +132,27 @@ Please fix the corresponding `.txt` file in the repo root: one `owner/repo` per
   - {{ if $repo.Files.IconPng.Pass }}[x] [icon.png]({{ $repo.Files.IconPng.URL }}){{ else }}[ ] `icon.png`{{ end }}
   - {{ if $repo.Files.PreviewPng.Pass }}[x] [preview.png]({{ $repo.Files.PreviewPng.URL }}){{ else }}[ ] `preview.png`{{ end }}
   - {{ if $repo.Files.ReadmeMd.Pass }}[x] [README.md]({{ $repo.Files.ReadmeMd.URL }}){{ else }}[ ] `README.md`{{ end }}
-- {{ if $repo.Attrs.Pass }}[x]{{ else }}[ ]{{ end }} Attributes that must exist in `template.json`
+- {{ if $repo.Content.Pass }}[x]{{ else }}[ ]{{ end }} Content that must exist in `package.zip`
+{{ if $repo.Content.Error }}  - [ ] {{ $repo.Content.Error }}
+{{ end }}{{ range $repo.Content.Rules }}  - {{ if .Pass }}[x]{{ else }}[ ]{{ end }} At least one template `.md` file other than README{{ if .Files }}: {{ range $i, $f := .Files }}{{ if $i }}, {{ end }}`{{ $f }}`{{ end }}{{ end }}
+{{ end }}- {{ if $repo.Secrets.Pass }}[x]{{ else }}[ ]{{ end }} No secrets or credentials in `package.zip` or the repository
+{{ if $repo.Secrets.Error }}  - [ ] {{ $repo.Secrets.Error }}
+{{ end }}{{ range $repo.Secrets.Findings }}  - [ ] {{ .Kind }} in {{ .Source }} `{{ .File }}`{{ if .Line }} line {{ .Line }}{{ end }}{{ if .Redacted }}: `{{ .Redacted }}`{{ end }}
+{{ end }}- {{ if $repo.License.Pass }}[x] License: `{{ $repo.License.ID }}`{{ else }}[ ] A recognized license in `LICENSE`{{ end }}
+{{ range $repo.License.Files }}  - {{ if .ID }}[x]{{ else }}[ ]{{ end }} {{ if .URL }}[{{ .File }}]({{ .URL }}){{ else }}`{{ .File }}`{{ end }} in {{ .Source }}: {{ if .ID }}`{{ .ID }}`{{ else }}license not recognized{{ end }}
+{{ end }}- {{ if $repo.Attrs.Pass }}[x]{{ else }}[ ]{{ end }} Attributes that must exist in `template.json`
   - {{ if $repo.Attrs.Name.Pass }}[x] `name`: `{{ $repo.Attrs.Name.Value }}`{{ else }}[ ] `name`{{ end }}
     - {{ if $repo.Attrs.Name.Valid }}[x]{{ else }}[ ]{{ end }} Is a valid name
     - {{ if $repo.Attrs.Name.Unique }}[x]{{ else }}[ ]{{ end }} Not conflict with other template name
   - {{ if $repo.Attrs.Version.Pass }}[x] `version`: `{{ $repo.Attrs.Version.Value }}`{{ else }}[ ] `version`{{ end }}
   - {{ if $repo.Attrs.Author.Pass }}[x] `author`: `{{ $repo.Attrs.Author.Value }}`{{ else }}[ ] `author`{{ end }}
   - {{ if $repo.Attrs.URL.Pass }}[x] `url`: [{{ $repo.Attrs.URL.Value }}]({{ $repo.Attrs.URL.Value }}){{ else }}[ ] `url`{{ end }}
-
+{{ if $repo.Attrs.Readme.Files }}  - {{ if $repo.Attrs.Readme.Pass }}[x]{{ else }}[ ]{{ end }} `readme`: README files that must exist
+{{ range $repo.Attrs.Readme.Files }}    - {{ if .Pass }}[x] `{{ .Locale }}`: [{{ .Path }}]({{ .URL }}){{ else }}[ ] `{{ .Locale }}`: `{{ .Path }}`{{ end }}
+{{ end }}{{ end }}{{ if or $repo.Attrs.Keywords.Normalized $repo.Attrs.Keywords.Unknown }}- Keywords in `template.json` (for reference only)
+{{ range $repo.Attrs.Keywords.Normalized }}  - `{{ .From }}` is normalized to `{{ .To }}`
+{{ end }}{{ range $repo.Attrs.Keywords.Unknown }}  - `{{ . }}` is not in [keywords.json](https://github.com/siyuan-note/bazaar/blob/main/keywords.json)
+{{ end }}{{ end }}
 ---
 {{ end }}
 {{ end }}
@@ -118,7 +171,10 @@ Please fix the corresponding `.txt` file in the repo root: one `owner/repo` per
 {{ range $i, $repo := .Themes }}
 #### [{{ $repo.RepoInfo.Path }}]({{ $repo.RepoInfo.Home }}){{ if $repo.MaintainerChanged }} (Change Maintainer){{ end }}
 
-- {{ if $repo.Release.Pass }}[x]{{ else }}[ ]{{ end }} Release that must exist
+- {{ if $repo.RepoInfo.Health.Pass }}[x]{{ else }}[ ]{{ end }} Repository status
+{{ if $repo.RepoInfo.Health.Error }}  - [ ] {{ $repo.RepoInfo.Health.Error }}
+{{ end }}{{ range $repo.RepoInfo.Health.Items }}  - {{ if .Pass }}[x]{{ else }}[ ]{{ end }} {{ .Name }}{{ if .Detail }}: {{ .Detail }}{{ end }}
+{{ end }}- {{ if $repo.Release.Pass }}[x]{{ else }}[ ]{{ end }} Release that must exist
   - {{ if $repo.Release.LatestRelease.Pass }}[x] [Current Latest Release]({{ $repo.Release.LatestRelease.URL }}){{ else }}[ ] Current Latest Release{{ end }}
   - {{ if $repo.Release.LatestRelease.PackageZip.Pass }}[x] [package.zip]({{ $repo.Release.LatestRelease.PackageZip.URL }}){{ else }}[ ] `package.zip`{{ end }}
 - {{ if $repo.Files.Pass }}[x]{{ else }}[ ]{{ end }} Files that must exist
@@ -126,14 +182,29 @@ Please fix the corresponding `.txt` file in the repo root: one `owner/repo` per
   - {{ if $repo.Files.IconPng.Pass }}[x] [icon.png]({{ $repo.Files.IconPng.URL }}){{ else }}[ ] `icon.png`{{ end }}
   - {{ if $repo.Files.PreviewPng.Pass }}[x] [preview.png]({{ $repo.Files.PreviewPng.URL }}){{ else }}[ ] `preview.png`{{ end }}
   - {{ if $repo.Files.ReadmeMd.Pass }}[x] [README.md]({{ $repo.Files.ReadmeMd.URL }}){{ else }}[ ] `README.md`{{ end }}
-- {{ if $repo.Attrs.Pass }}[x]{{ else }}[ ]{{ end }} Attributes that must exist in `theme.json`
+- {{ if $repo.Content.Pass }}[x]{{ else }}[ ]{{ end }} Content that must exist in `package.zip`
+{{ if $repo.Content.Error }}  - [ ] {{ $repo.Content.Error }}
+{{ end }}{{ range $repo.Content.Rules }}  - {{ if .Pass }}[x]{{ else }}[ ]{{ end }} `{{ .Name }}`
+{{ end }}{{ range $repo.Content.CSS }}  - {{ if eq .Severity "error" }}[ ]{{ else }}:warning:{{ end }} `{{ .File }}`{{ if .Line }} line {{ .Line }}, column {{ .Column }}{{ end }}: {{ .Message }}
+{{ end }}- {{ if $repo.Secrets.Pass }}[x]{{ else }}[ ]{{ end }} No secrets or credentials in `package.zip` or the repository
+{{ if $repo.Secrets.Error }}  - [ ] {{ $repo.Secrets.Error }}
+{{ end }}{{ range $repo.Secrets.Findings }}  - [ ] {{ .Kind }} in {{ .Source }} `{{ .File }}`{{ if .Line }} line {{ .Line }}{{ end }}{{ if .Redacted }}: `{{ .Redacted }}`{{ end }}
+{{ end }}- {{ if $repo.License.Pass }}[x] License: `{{ $repo.License.ID }}`{{ else }}[ ] A recognized license in `LICENSE`{{ end }}
+{{ range $repo.License.Files }}  - {{ if .ID }}[x]{{ else }}[ ]{{ end }} {{ if .URL }}[{{ .File }}]({{ .URL }}){{ else }}`{{ .File }}`{{ end }} in {{ .Source }}: {{ if .ID }}`{{ .ID }}`{{ else }}license not recognized{{ end }}
+{{ end }}- {{ if $repo.Attrs.Pass }}[x]{{ else }}[ ]{{ end }} Attributes that must exist in `theme.json`
   - {{ if $repo.Attrs.Name.Pass }}[x] `name`: `{{ $repo.Attrs.Name.Value }}`{{ else }}[ ] `name`{{ end }}
     - {{ if $repo.Attrs.Name.Valid }}[x]{{ else }}[ ]{{ end }} Is a valid name
     - {{ if $repo.Attrs.Name.Unique }}[x]{{ else }}[ ]{{ end }} Not conflict with other theme name
   - {{ if $repo.Attrs.Version.Pass }}[x] `version`: `{{ $repo.Attrs.Version.Value }}`{{ else }}[ ] `version`{{ end }}
   - {{ if $repo.Attrs.Author.Pass }}[x] `author`: `{{ $repo.Attrs.Author.Value }}`{{ else }}[ ] `author`{{ end }}
   - {{ if $repo.Attrs.URL.Pass }}[x] `url`: [{{ $repo.Attrs.URL.Value }}]({{ $repo.Attrs.URL.Value }}){{ else }}[ ] `url`{{ end }}
-
+{{ if $repo.Attrs.Readme.Files }}  - {{ if $repo.Attrs.Readme.Pass }}[x]{{ else }}[ ]{{ end }} `readme`: README files that must exist
+{{ range $repo.Attrs.Readme.Files }}    - {{ if .Pass }}[x] `{{ .Locale }}`: [{{ .Path }}]({{ .URL }}){{ else }}[ ] `{{ .Locale }}`: `{{ .Path }}`{{ end }}
+{{ end }}{{ end }}{{ range $repo.Attrs.Enums.Invalid }}  - [ ] `{{ .Field }}` has unknown values: {{ range $i, $v := .Values }}{{ if $i }}, {{ end }}`{{ $v }}`{{ end }} (allowed: {{ range $i, $v := .Allowed }}{{ if $i }}, {{ end }}`{{ $v }}`{{ end }})
+{{ end }}{{ if or $repo.Attrs.Keywords.Normalized $repo.Attrs.Keywords.Unknown }}- Keywords in `theme.json` (for reference only)
+{{ range $repo.Attrs.Keywords.Normalized }}  - `{{ .From }}` is normalized to `{{ .To }}`
+{{ end }}{{ range $repo.Attrs.Keywords.Unknown }}  - `{{ . }}` is not in [keywords.json](https://github.com/siyuan-note/bazaar/blob/main/keywords.json)
+{{ end }}{{ end }}
 ---
 {{ end }}
 {{ end }}
@@ -152,7 +223,10 @@ Please fix the corresponding `.txt` file in the repo root: one `owner/repo` per
 {{ range $i, $repo := .Widgets }}
 #### [{{ $repo.RepoInfo.Path }}]({{ $repo.RepoInfo.Home }}){{ if $repo.MaintainerChanged }} (Change Maintainer){{ end }}
 
-- {{ if $repo.Release.Pass }}[x]{{ else }}[ ]{{ end }} Release that must exist
+- {{ if $repo.RepoInfo.Health.Pass }}[x]{{ else }}[ ]{{ end }} Repository status
+{{ if $repo.RepoInfo.Health.Error }}  - [ ] {{ $repo.RepoInfo.Health.Error }}
+{{ end }}{{ range $repo.RepoInfo.Health.Items }}  - {{ if .Pass }}[x]{{ else }}[ ]{{ end }} {{ .Name }}{{ if .Detail }}: {{ .Detail }}{{ end }}
+{{ end }}- {{ if $repo.Release.Pass }}[x]{{ else }}[ ]{{ end }} Release that must exist
   - {{ if $repo.Release.LatestRelease.Pass }}[x] [Current Latest Release]({{ $repo.Release.LatestRelease.URL }}){{ else }}[ ] Current Latest Release{{ end }}
   - {{ if $repo.Release.LatestRelease.PackageZip.Pass }}[x] [package.zip]({{ $repo.Release.LatestRelease.PackageZip.URL }}){{ else }}[ ] `package.zip`{{ end }}
 - {{ if $repo.Files.Pass }}[x]{{ else }}[ ]{{ end }} Files that must exist
@@ -160,14 +234,27 @@ Please fix the corresponding `.txt` file in the repo root: one `owner/repo` per
   - {{ if $repo.Files.IconPng.Pass }}[x] [icon.png]({{ $repo.Files.IconPng.URL }}){{ else }}[ ] `icon.png`{{ end }}
   - {{ if $repo.Files.PreviewPng.Pass }}[x] [preview.png]({{ $repo.Files.PreviewPng.URL }}){{ else }}[ ] `preview.png`{{ end }}
   - {{ if $repo.Files.ReadmeMd.Pass }}[x] [README.md]({{ $repo.Files.ReadmeMd.URL }}){{ else }}[ ] `README.md`{{ end }}
-- {{ if $repo.Attrs.Pass }}[x]{{ else }}[ ]{{ end }} Attributes that must exist in `widget.json`
+- {{ if $repo.Content.Pass }}[x]{{ else }}[ ]{{ end }} Content that must exist in `package.zip`
+{{ if $repo.Content.Error }}  - [ ] {{ $repo.Content.Error }}
+{{ end }}{{ range $repo.Content.Rules }}  - {{ if .Pass }}[x]{{ else }}[ ]{{ end }} `{{ .Name }}`
+{{ end }}- {{ if $repo.Secrets.Pass }}[x]{{ else }}[ ]{{ end }} No secrets or credentials in `package.zip` or the repository
+{{ if $repo.Secrets.Error }}  - [ ] {{ $repo.Secrets.Error }}
+{{ end }}{{ range $repo.Secrets.Findings }}  - [ ] {{ .Kind }} in {{ .Source }} `{{ .File }}`{{ if .Line }} line {{ .Line }}{{ end }}{{ if .Redacted }}: `{{ .Redacted }}`{{ end }}
+{{ end }}- {{ if $repo.License.Pass }}[x] License: `{{ $repo.License.ID }}`{{ else }}[ ] A recognized license in `LICENSE`{{ end }}
+{{ range $repo.License.Files }}  - {{ if .ID }}[x]{{ else }}[ ]{{ end }} {{ if .URL }}[{{ .File }}]({{ .URL }}){{ else }}`{{ .File }}`{{ end }} in {{ .Source }}: {{ if .ID }}`{{ .ID }}`{{ else }}license not recognized{{ end }}
+{{ end }}- {{ if $repo.Attrs.Pass }}[x]{{ else }}[ ]{{ end }} Attributes that must exist in `widget.json`
   - {{ if $repo.Attrs.Name.Pass }}[x] `name`: `{{ $repo.Attrs.Name.Value }}`{{ else }}[ ] `name`{{ end }}
     - {{ if $repo.Attrs.Name.Valid }}[x]{{ else }}[ ]{{ end }} Is a valid name
     - {{ if $repo.Attrs.Name.Unique }}[x]{{ else }}[ ]{{ end }} Not conflict with other widget name
   - {{ if $repo.Attrs.Version.Pass }}[x] `version`: `{{ $repo.Attrs.Version.Value }}`{{ else }}[ ] `version`{{ end }}
   - {{ if $repo.Attrs.Author.Pass }}[x] `author`: `{{ $repo.Attrs.Author.Value }}`{{ else }}[ ] `author`{{ end }}
   - {{ if $repo.Attrs.URL.Pass }}[x] `url`: [{{ $repo.Attrs.URL.Value }}]({{ $repo.Attrs.URL.Value }}){{ else }}[ ] `url`{{ end }}
-
+{{ if $repo.Attrs.Readme.Files }}  - {{ if $repo.Attrs.Readme.Pass }}[x]{{ else }}[ ]{{ end }} `readme`: README files that must exist
+{{ range $repo.Attrs.Readme.Files }}    - {{ if .Pass }}[x] `{{ .Locale }}`: [{{ .Path }}]({{ .URL }}){{ else }}[ ] `{{ .Locale }}`: `{{ .Path }}`{{ end }}
+{{ end }}{{ end }}{{ if or $repo.Attrs.Keywords.Normalized $repo.Attrs.Keywords.Unknown }}- Keywords in `widget.json` (for reference only)
+{{ range $repo.Attrs.Keywords.Normalized }}  - `{{ .From }}` is normalized to `{{ .To }}`
+{{ end }}{{ range $repo.Attrs.Keywords.Unknown }}  - `{{ . }}` is not in [keywords.json](https://github.com/siyuan-note/bazaar/blob/main/keywords.json)
+{{ end }}{{ end }}
 ---
 {{ end }}
 {{ end }}